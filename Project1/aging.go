@@ -0,0 +1,257 @@
+package main
+
+import (
+	"container/heap"
+	"fmt"
+)
+
+// aging raises the effective priority of a waiting process by step every
+// interval ticks it spends ready but not running, so that a steady stream of
+// higher priority work cannot starve it. A step of 0 disables aging.
+type aging struct {
+	step     int64
+	interval int64
+}
+
+// newAging reads the parameters:
+// • aging: priority levels gained per interval of waiting, 0 for none (default 0)
+// • aging_interval: ticks of waiting per step (default 10)
+func newAging(params Parameters) (aging, error) {
+	a := aging{step: params.Get("aging", 0)}
+	if a.step < 0 {
+		return aging{}, fmt.Errorf("%w: aging must not be negative, got %d", ErrInvalidParameter, a.step)
+	}
+	var err error
+	a.interval, err = params.positive("aging_interval", 10)
+	return a, err
+}
+
+func (a aging) Parameters() Parameters {
+	return Parameters{"aging": a.step, "aging_interval": a.interval}
+}
+
+// schedule runs processes by effective priority, breaking ties by shortest
+// remaining time, and reports how each process's priority aged if it does.
+func (a aging) schedule(processes []Process, preemptive bool, m machine) Result {
+	// The run queues of every CPU share the aging of the tasks, which may
+	// move between them.
+	var (
+		state   = make([]agingState, len(processes))
+		history = make([]PriorityHistory, len(processes))
+	)
+	// Every process starts at its base priority on arrival, even one that
+	// first blocks for I/O.
+	for i, p := range processes {
+		history[i] = PriorityHistory{PID: p.ProcessID, Job: p.Job, Changes: []PriorityChange{{At: p.ArrivalTime, Priority: p.Priority}}}
+	}
+	res := m.simulate(processes, func() policy {
+		return &agingPolicy{aging: a, preemptive: preemptive, ready: agingHeap{state: state}, state: state, history: history}
+	})
+	if a.step > 0 {
+		res.Priorities = history
+	}
+	return res
+}
+
+// agingState is how far a task has aged.
+type agingState struct {
+	// waited is the time the task spent ready up to since, when it last
+	// became ready. since is -1 while the task runs.
+	waited int64
+	since  int64
+	// priority is the task's effective priority while it is ready, and
+	// index its place in the heap of its run queue.
+	priority int64
+	index    int
+	// ready counts the times the task has become ready.
+	ready int
+}
+
+// agingPolicy is the simulation state of a priority scheduler with aging.
+// The ready tasks are kept in a heap by effective priority, which is
+// updated as each task ages: at most once for every priority level, as
+// priorities only rise.
+type agingPolicy struct {
+	aging
+	preemptive bool
+	ready      agingHeap
+	// ages holds when the ready tasks next gain priority.
+	ages    ageQueue
+	state   []agingState
+	history []PriorityHistory
+}
+
+// effective returns the priority of t at now. Aging stops at the highest
+// priority, 1.
+func (p *agingPolicy) effective(t *task, now int64) int64 {
+	if p.step == 0 {
+		return t.Priority
+	}
+	prio := t.Priority - p.step*(p.waitedBy(t, now)/p.interval)
+	if prio < 1 {
+		prio = 1
+	}
+	if prio > t.Priority {
+		// Processes without a priority already run first.
+		return t.Priority
+	}
+	return prio
+}
+
+// waitedBy returns the time t has spent ready by now. A running task's wait
+// does not grow.
+func (p *agingPolicy) waitedBy(t *task, now int64) int64 {
+	s := &p.state[t.index]
+	if s.since < 0 {
+		return s.waited
+	}
+	return s.waited + now - s.since
+}
+
+// before reports whether a should run ahead of b at now.
+func (p *agingPolicy) before(a, b *task, now int64) bool {
+	switch pa, pb := p.effective(a, now), p.effective(b, now); {
+	case pa != pb:
+		return pa < pb
+	case a.remaining != b.remaining:
+		return a.remaining < b.remaining
+	default:
+		return byArrival(a, b)
+	}
+}
+
+// age raises the priorities of the ready tasks that have aged by now,
+// recording the changes.
+func (p *agingPolicy) age(now int64) {
+	for len(p.ages) > 0 && p.ages[0].at <= now {
+		e := heap.Pop(&p.ages).(ageEntry)
+		s := &p.state[e.t.index]
+		if s.since < 0 || s.ready != e.ready {
+			// The task has run since.
+			continue
+		}
+		s.priority = p.effective(e.t, e.at)
+		heap.Fix(&p.ready, s.index)
+		h := &p.history[e.t.index]
+		h.Changes = append(h.Changes, PriorityChange{At: e.at, Priority: s.priority})
+		p.scheduleAge(e.t, e.at)
+	}
+}
+
+// scheduleAge queues the next time the ready task t gains priority after
+// now, if it can.
+func (p *agingPolicy) scheduleAge(t *task, now int64) {
+	if p.step == 0 || p.effective(t, now) <= 1 {
+		return
+	}
+	at := now + p.interval - p.waitedBy(t, now)%p.interval
+	heap.Push(&p.ages, ageEntry{at: at, t: t, ready: p.state[t.index].ready})
+}
+
+func (p *agingPolicy) push(t *task, now int64) {
+	p.age(now)
+	s := &p.state[t.index]
+	s.since = now
+	s.ready++
+	s.priority = p.effective(t, now)
+	heap.Push(&p.ready, t)
+	p.scheduleAge(t, now)
+}
+
+func (p *agingPolicy) pop(now int64) (*task, int64) {
+	p.age(now)
+	t := heap.Pop(&p.ready).(*task)
+	s := &p.state[t.index]
+	s.waited, s.since = p.waitedBy(t, now), -1
+	return t, 0
+}
+
+func (p *agingPolicy) empty() bool            { return len(p.ready.tasks) == 0 }
+func (p *agingPolicy) ran(*task, int64, bool) {}
+
+func (p *agingPolicy) preempts(running *task, now int64) bool {
+	if !p.preemptive || p.empty() {
+		return false
+	}
+	p.age(now)
+	return p.before(p.ready.tasks[0], running, now)
+}
+
+// nextTimer returns the next time a ready task gains a step of priority.
+func (p *agingPolicy) nextTimer(now int64) (int64, bool) {
+	p.age(now)
+	for len(p.ages) > 0 {
+		e := p.ages[0]
+		if s := p.state[e.t.index]; s.since >= 0 && s.ready == e.ready {
+			return e.at, true
+		}
+		heap.Pop(&p.ages)
+	}
+	return 0, false
+}
+
+// fire records the priorities that aged at now.
+func (p *agingPolicy) fire(_ *task, now int64) { p.age(now) }
+
+// agingHeap is a heap of ready tasks by effective priority, then remaining
+// time, then arrival, keeping each task's place in its state.
+type agingHeap struct {
+	tasks []*task
+	state []agingState
+}
+
+func (h agingHeap) Len() int { return len(h.tasks) }
+
+func (h agingHeap) Less(i, j int) bool {
+	a, b := h.tasks[i], h.tasks[j]
+	switch pa, pb := h.state[a.index].priority, h.state[b.index].priority; {
+	case pa != pb:
+		return pa < pb
+	case a.remaining != b.remaining:
+		return a.remaining < b.remaining
+	default:
+		return byArrival(a, b)
+	}
+}
+
+func (h agingHeap) Swap(i, j int) {
+	h.tasks[i], h.tasks[j] = h.tasks[j], h.tasks[i]
+	h.state[h.tasks[i].index].index = i
+	h.state[h.tasks[j].index].index = j
+}
+
+func (h *agingHeap) Push(x interface{}) {
+	t := x.(*task)
+	h.state[t.index].index = len(h.tasks)
+	h.tasks = append(h.tasks, t)
+}
+
+func (h *agingHeap) Pop() interface{} {
+	t := h.tasks[len(h.tasks)-1]
+	h.tasks[len(h.tasks)-1] = nil
+	h.tasks = h.tasks[:len(h.tasks)-1]
+	return t
+}
+
+// ageEntry is a time a task is due to gain priority, if it is still in the
+// stint of being ready it was queued in.
+type ageEntry struct {
+	at    int64
+	t     *task
+	ready int
+}
+
+// ageQueue is a min-heap of ageEntry by time.
+type ageQueue []ageEntry
+
+func (q ageQueue) Len() int            { return len(q) }
+func (q ageQueue) Less(i, j int) bool  { return q[i].at < q[j].at }
+func (q ageQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *ageQueue) Push(x interface{}) { *q = append(*q, x.(ageEntry)) }
+
+func (q *ageQueue) Pop() interface{} {
+	old := *q
+	e := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return e
+}
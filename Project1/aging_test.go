@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAging_schedule(t *testing.T) {
+	t.Parallel()
+	// Process 4 has the lowest priority and a stream of priority 1 work
+	// arriving behind it.
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 3, Priority: 1},
+		{ProcessID: 2, BurstDuration: 3, ArrivalTime: 1, Priority: 1},
+		{ProcessID: 3, BurstDuration: 3, ArrivalTime: 2, Priority: 1},
+		{ProcessID: 4, BurstDuration: 2, Priority: 5},
+		{ProcessID: 5, BurstDuration: 3, ArrivalTime: 4, Priority: 1},
+		{ProcessID: 6, BurstDuration: 3, ArrivalTime: 7, Priority: 1},
+		{ProcessID: 7, BurstDuration: 3, ArrivalTime: 10, Priority: 1},
+	}
+	tests := []struct {
+		name           string
+		aging          aging
+		preemptive     bool
+		wantCompletion int64
+		wantHistory    []PriorityChange
+	}{
+		{
+			name:           "without aging",
+			aging:          aging{interval: 3},
+			preemptive:     true,
+			wantCompletion: 20,
+		},
+		{
+			name:           "preemptive",
+			aging:          aging{step: 2, interval: 3},
+			preemptive:     true,
+			wantCompletion: 8,
+			wantHistory:    []PriorityChange{{At: 0, Priority: 5}, {At: 3, Priority: 3}, {At: 6, Priority: 1}},
+		},
+		{
+			name:           "non-preemptive",
+			aging:          aging{step: 1, interval: 3},
+			wantCompletion: 14,
+			wantHistory: []PriorityChange{
+				{At: 0, Priority: 5}, {At: 3, Priority: 4}, {At: 6, Priority: 3},
+				{At: 9, Priority: 2}, {At: 12, Priority: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := tt.aging.schedule(processes, tt.preemptive, machine{})
+			if c := got.Processes[3].Completion; c != tt.wantCompletion {
+				t.Errorf("process 4 completion = %d, want %d", c, tt.wantCompletion)
+			}
+			var history []PriorityChange
+			if got.Priorities != nil {
+				history = got.Priorities[3].Changes
+			}
+			if !reflect.DeepEqual(history, tt.wantHistory) {
+				t.Errorf("process 4 priorities = %v, want %v", history, tt.wantHistory)
+			}
+		})
+	}
+}
+
+// TestAging_schedule_running checks that only the time a process spends ready
+// ages it, not the time it runs.
+func TestAging_schedule_running(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 100, Priority: 10},
+		{ProcessID: 2, BurstDuration: 10, ArrivalTime: 30, Priority: 8},
+	}
+	tests := []struct {
+		name        string
+		aging       aging
+		preemptive  bool
+		wantGantt   []TimeSlice
+		wantHistory []PriorityChange
+	}{
+		{
+			name:       "preemptive",
+			aging:      aging{step: 1, interval: 10},
+			preemptive: true,
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 30},
+				{PID: 2, Start: 30, Stop: 40},
+				{PID: 1, Start: 40, Stop: 110},
+			},
+			// Process 1 ages only while process 2 runs.
+			wantHistory: []PriorityChange{{At: 0, Priority: 10}, {At: 40, Priority: 9}},
+		},
+		{
+			name:  "non-preemptive",
+			aging: aging{step: 1, interval: 10},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 100},
+				{PID: 2, Start: 100, Stop: 110},
+			},
+			wantHistory: []PriorityChange{{At: 0, Priority: 10}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := tt.aging.schedule(processes, tt.preemptive, machine{})
+			if !reflect.DeepEqual(got.Gantt, tt.wantGantt) {
+				t.Errorf("Gantt = %v, want %v", got.Gantt, tt.wantGantt)
+			}
+			if history := got.Priorities[0].Changes; !reflect.DeepEqual(history, tt.wantHistory) {
+				t.Errorf("process 1 priorities = %v, want %v", history, tt.wantHistory)
+			}
+		})
+	}
+}
+
+// TestAging_schedule_ioOnly checks that a process that never becomes ready,
+// only ever waiting for I/O, still reports its base priority.
+func TestAging_schedule_ioOnly(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, Priority: 2, Bursts: []Burst{{IO: true, Duration: 3}}},
+		{ProcessID: 2, BurstDuration: 5, Priority: 3},
+	}
+	got := aging{step: 1, interval: 10}.schedule(processes, true, machine{})
+	want := []PriorityHistory{
+		{PID: 1, Changes: []PriorityChange{{At: 0, Priority: 2}}},
+		{PID: 2, Changes: []PriorityChange{{At: 0, Priority: 3}}},
+	}
+	if !reflect.DeepEqual(got.Priorities, want) {
+		t.Errorf("Priorities = %v, want %v", got.Priorities, want)
+	}
+	var w bytes.Buffer
+	outputResult(&w, "Priority", got)
+	if !strings.Contains(w.String(), "Effective priority") {
+		t.Errorf("outputResult() = %v, want the effective priorities", w.String())
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"container/heap"
+	"fmt"
+)
+
+func init() {
+	Register("cfs", newCFS)
+}
+
+// niceWeights is the Linux load weight of each nice level from -20 to 19;
+// each level is worth about 10% more CPU than the next.
+var niceWeights = [40]int64{
+	88761, 71755, 56483, 46273, 36291,
+	29154, 23254, 18705, 14949, 11916,
+	9548, 7620, 6100, 4904, 3906,
+	3121, 2501, 1991, 1586, 1277,
+	1024, 820, 655, 526, 423,
+	335, 272, 215, 172, 137,
+	110, 87, 70, 56, 45,
+	36, 29, 23, 18, 15,
+}
+
+// nice maps a priority of [1-50] onto a nice level of [-20, 19]. Processes
+// without a priority are nice 0.
+func nice(p Process) int64 {
+	if p.Priority < 1 || p.Priority > 50 {
+		return 0
+	}
+	return ((p.Priority-1)*39+24)/49 - 20
+}
+
+// cfsWeight returns the load weight of a process.
+func cfsWeight(p Process) int64 {
+	return niceWeights[nice(p)+20]
+}
+
+// cfs is a Completely Fair Scheduler simulation. The runnable process with
+// the least virtual runtime runs next, for its weighted share of the target
+// latency; virtual runtime grows more slowly the higher a process's weight.
+type cfs struct {
+	machine
+	latency     int64
+	granularity int64
+}
+
+// newCFS builds a CFS from the parameters:
+// • latency: ticks in which every runnable process should run once (default 20)
+// • granularity: shortest slice a process is given (default 2)
+func newCFS(params Parameters) (Scheduler, error) {
+	latency, err := params.positive("latency", 20)
+	if err != nil {
+		return nil, err
+	}
+	granularity, err := params.positive("granularity", 2)
+	if err != nil {
+		return nil, err
+	}
+	if granularity > latency {
+		return nil, fmt.Errorf("%w: granularity %d is longer than latency %d", ErrInvalidParameter, granularity, latency)
+	}
+	m, err := newMachine(params)
+	return cfs{machine: m, latency: latency, granularity: granularity}, err
+}
+
+func (cfs) Name() string  { return "cfs" }
+func (cfs) Title() string { return "Completely fair" }
+
+func (s cfs) Parameters() Parameters {
+	return s.parameters(Parameters{"latency": s.latency, "granularity": s.granularity})
+}
+
+func (s cfs) Schedule(processes []Process) Result {
+	return s.simulate(processes, func() policy {
+		return &cfsPolicy{cfs: s, vruntime: make(map[*task]int64)}
+	})
+}
+
+// cfsPolicy is the simulation state of a cfs.
+type cfsPolicy struct {
+	cfs
+	ready    cfsQueue
+	vruntime map[*task]int64
+	// minVruntime only moves forward; arrivals start from it, so they never
+	// preempt the running process but wait for its slice to end.
+	minVruntime int64
+	// weight is the total weight of the queued processes.
+	weight int64
+	seq    int64
+}
+
+func (p *cfsPolicy) push(t *task, _ int64) {
+	if v, ok := p.vruntime[t]; !ok || v < p.minVruntime {
+		p.vruntime[t] = p.minVruntime
+	}
+	p.weight += cfsWeight(t.Process)
+	p.seq++
+	heap.Push(&p.ready, cfsEntry{t: t, vruntime: p.vruntime[t], seq: p.seq})
+}
+
+// pop takes the least virtual runtime and gives it its share of the latency
+// period, which stretches when too many processes are runnable to give each
+// the minimum granularity.
+func (p *cfsPolicy) pop(int64) (*task, int64) {
+	e := heap.Pop(&p.ready).(cfsEntry)
+
+	period := p.latency
+	if n := int64(len(p.ready)) + 1; n*p.granularity > period {
+		period = n * p.granularity
+	}
+	slice := period * cfsWeight(e.t.Process) / p.weight
+	p.weight -= cfsWeight(e.t.Process)
+	if slice < p.granularity {
+		slice = p.granularity
+	}
+	return e.t, slice
+}
+
+func (p *cfsPolicy) empty() bool { return len(p.ready) == 0 }
+
+// ran advances the virtual runtime of t by d ticks scaled by the nice 0
+// weight over its own, in 1/1024ths of a tick.
+func (p *cfsPolicy) ran(t *task, d int64, _ bool) {
+	p.vruntime[t] += d * niceWeights[20] * 1024 / cfsWeight(t.Process)
+
+	least := p.vruntime[t]
+	if len(p.ready) > 0 && p.ready[0].vruntime < least {
+		least = p.ready[0].vruntime
+	}
+	if least > p.minVruntime {
+		p.minVruntime = least
+	}
+}
+
+// cfsEntry is a runnable process ordered by virtual runtime, then by how long
+// it has been waiting.
+type cfsEntry struct {
+	t        *task
+	vruntime int64
+	seq      int64
+}
+
+// cfsQueue is a min-heap of runnable processes.
+type cfsQueue []cfsEntry
+
+func (q cfsQueue) Len() int { return len(q) }
+
+func (q cfsQueue) Less(i, j int) bool {
+	if q[i].vruntime != q[j].vruntime {
+		return q[i].vruntime < q[j].vruntime
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q cfsQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *cfsQueue) Push(x interface{}) { *q = append(*q, x.(cfsEntry)) }
+
+func (q *cfsQueue) Pop() interface{} {
+	old := *q
+	e := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return e
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_nice(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		priority int64
+		want     int64
+	}{
+		{priority: 0, want: 0},
+		{priority: 1, want: -20},
+		{priority: 25, want: -1},
+		{priority: 50, want: 19},
+		{priority: 51, want: 0},
+	}
+	for _, tt := range tests {
+		if got := nice(Process{Priority: tt.priority}); got != tt.want {
+			t.Errorf("nice(%d) = %v, want %v", tt.priority, got, tt.want)
+		}
+	}
+}
+
+func TestCFS_Schedule(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		processes []Process
+		wantGantt []TimeSlice
+	}{
+		{
+			name: "equal weights share the latency",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 4},
+				{ProcessID: 2, BurstDuration: 4},
+			},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 2},
+				{PID: 2, Start: 2, Stop: 4},
+				{PID: 1, Start: 4, Stop: 6},
+				{PID: 2, Start: 6, Stop: 8},
+			},
+		},
+		{
+			name: "heavier process gets longer slices",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 4, Priority: 1},
+				{ProcessID: 2, BurstDuration: 4, Priority: 50},
+			},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 3},
+				{PID: 2, Start: 3, Stop: 4},
+				{PID: 1, Start: 4, Stop: 5},
+				{PID: 2, Start: 5, Stop: 8},
+			},
+		},
+		{
+			name: "arrival waits for the running slice",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 10},
+				{ProcessID: 2, BurstDuration: 1, ArrivalTime: 2},
+			},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 4},
+				{PID: 2, Start: 4, Stop: 5},
+				{PID: 1, Start: 5, Stop: 9},
+				{PID: 1, Start: 9, Stop: 11},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := cfs{latency: 4, granularity: 1}.Schedule(tt.processes)
+			if !reflect.DeepEqual(got.Gantt, tt.wantGantt) {
+				t.Errorf("Gantt = %v, want %v", got.Gantt, tt.wantGantt)
+			}
+		})
+	}
+}
+
+func Test_newCFS(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		params  Parameters
+		wantErr error
+	}{
+		{name: "defaults"},
+		{name: "zero latency", params: Parameters{"latency": 0}, wantErr: ErrInvalidParameter},
+		{name: "granularity over latency", params: Parameters{"latency": 4, "granularity": 5}, wantErr: ErrInvalidParameter},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := newCFS(tt.params); !errors.Is(err, tt.wantErr) {
+				t.Errorf("newCFS() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
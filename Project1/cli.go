@@ -0,0 +1,332 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Exit codes of the scheduler binary.
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+// paramFlag collects repeated -param name=value flags.
+type paramFlag Parameters
+
+func (p paramFlag) String() string {
+	pairs := make([]string, 0, len(p))
+	for k, v := range p {
+		pairs = append(pairs, k+"="+strconv.FormatInt(v, 10))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (p paramFlag) Set(s string) error {
+	k, v, ok := strings.Cut(s, "=")
+	if !ok || k == "" {
+		return fmt.Errorf("%w: expected name=value, got %q", ErrInvalidParameter, s)
+	}
+	i, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidParameter, k, err)
+	}
+	p[k] = i
+	return nil
+}
+
+// commonParameters are the parameters any scheduler may be given, whether or
+// not it uses them: the quantum of the -quantum flag and those of the machine.
+var commonParameters = []string{"quantum", "switch", "cpus", "percore", "little", "slowdown", "energy"}
+
+// checkParameters returns an error naming a key of params that none of
+// schedulers takes, listing the keys they do.
+func checkParameters(params Parameters, schedulers []Scheduler) error {
+	valid := make(map[string]bool)
+	for _, key := range commonParameters {
+		valid[key] = true
+	}
+	for _, s := range schedulers {
+		for key := range s.Parameters() {
+			valid[key] = true
+		}
+	}
+	var unknown []string
+	for key := range params {
+		if !valid[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+	keys := make([]string, 0, len(valid))
+	for key := range valid {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return fmt.Errorf("%w: unknown parameter %q, want one of %s", ErrInvalidParameter, unknown[0], strings.Join(keys, ", "))
+}
+
+// config is the parsed command line.
+type config struct {
+	algorithms []string
+	params     Parameters
+	format     string
+	output     string
+	slices     string
+	window     int64
+	windows    string
+	list       bool
+	workload   string
+}
+
+// parseArgs parses the command line, args[0] being the program name.
+func parseArgs(args []string, stderr io.Writer) (config, error) {
+	var (
+		cfg = config{params: make(Parameters)}
+		fs  = flag.NewFlagSet(args[0], flag.ContinueOnError)
+
+		algorithms = fs.String("a", strings.Join(defaultAlgorithms, ","), `comma separated algorithms to run, or "all"`)
+		setParams  = schedulerFlags(fs, cfg.params)
+	)
+	fs.StringVar(&cfg.format, "format", "text", "output format: "+strings.Join(formatNames(), ", "))
+	fs.StringVar(&cfg.output, "o", "", "write output to `file` instead of stdout")
+	fs.StringVar(&cfg.slices, "slices", "", "`file` for the per-slice Gantt chart of the csv format (default next to -o)")
+	fs.Int64Var(&cfg.window, "window", 0, "also report throughput over successive windows of this many ticks")
+	fs.StringVar(&cfg.windows, "windows", "", "`file` for the windowed throughput of the csv format (default next to -o)")
+	fs.BoolVar(&cfg.list, "list", false, "list the available algorithms and exit")
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(stderr, "Usage: %s [flags] <workload.csv | ->\n", fs.Name())
+		_, _ = fmt.Fprintf(stderr, "       %s generate [flags]\n", fs.Name())
+		_, _ = fmt.Fprintf(stderr, "       %s experiment [flags] [workload.csv ...]\n\n", fs.Name())
+		_, _ = fmt.Fprintln(stderr, "Schedules the processes in a workload CSV, read from stdin when given -.")
+		_, _ = fmt.Fprintf(stderr, "Algorithms: %s\n\nFlags:\n", strings.Join(Algorithms(), ", "))
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args[1:]); err != nil {
+		return cfg, err
+	}
+	setParams()
+	if cfg.list {
+		return cfg, nil
+	}
+
+	if *algorithms == "all" {
+		cfg.algorithms = Algorithms()
+	} else {
+		cfg.algorithms = strings.Split(*algorithms, ",")
+	}
+	if _, ok := formats[cfg.format]; !ok {
+		return cfg, usageError(fs, fmt.Errorf("%w: %q", ErrUnknownFormat, cfg.format))
+	}
+	if cfg.window < 0 {
+		return cfg, usageError(fs, fmt.Errorf("%w: window must not be negative, got %d", ErrInvalidArgs, cfg.window))
+	}
+	if fs.NArg() != 1 {
+		return cfg, usageError(fs, fmt.Errorf("%w: must give a scheduling file to process", ErrInvalidArgs))
+	}
+	cfg.workload = fs.Arg(0)
+	if cfg.format == "csv" && cfg.output != "" {
+		ext := filepath.Ext(cfg.output)
+		if cfg.slices == "" {
+			cfg.slices = strings.TrimSuffix(cfg.output, ext) + "_slices" + ext
+		}
+		if cfg.windows == "" && cfg.window > 0 {
+			cfg.windows = strings.TrimSuffix(cfg.output, ext) + "_windows" + ext
+		}
+	}
+	// The csv format writes the windows to a file of their own, and the
+	// charts have no room for them.
+	if cfg.window > 0 && !(cfg.format == "text" || cfg.format == "json" || cfg.format == "csv" && cfg.windows != "") {
+		return cfg, usageError(fs, fmt.Errorf("%w: -window needs the text or json format, or csv with -o or -windows", ErrInvalidArgs))
+	}
+	return cfg, nil
+}
+
+// schedulerFlags defines on fs the flags that set scheduler parameters. The
+// function it returns copies those given into params once fs is parsed.
+func schedulerFlags(fs *flag.FlagSet, params Parameters) func() {
+	var (
+		quantum    = fs.Int64("quantum", 0, "time quantum for round-robin style algorithms (default per algorithm)")
+		switchCost = fs.Int64("switch", 0, "context switch cost in ticks")
+		cpus       = fs.Int64("cpus", 1, "number of CPUs")
+		perCore    = fs.Bool("percore", false, "give each CPU its own run queue, balancing load by migration")
+		little     = fs.Int64("little", 0, "number of the CPUs that run at 1/slowdown speed (-param slowdown=N, default 2)")
+		energy     = fs.Bool("energy", false, "place processes on the CPUs that run them for the least energy")
+	)
+	fs.Var(paramFlag(params), "param", "algorithm parameter as name=value; may be repeated")
+	return func() {
+		fs.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "quantum":
+				params["quantum"] = *quantum
+			case "switch":
+				params["switch"] = *switchCost
+			case "cpus":
+				params["cpus"] = *cpus
+			case "percore":
+				if *perCore {
+					params["percore"] = 1
+				}
+			case "little":
+				params["little"] = *little
+			case "energy":
+				if *energy {
+					params["energy"] = 1
+				}
+			}
+		})
+	}
+}
+
+// usageError reports err followed by the usage text, as the flag package does
+// for its own errors, and returns err.
+func usageError(fs *flag.FlagSet, err error) error {
+	_, _ = fmt.Fprintln(fs.Output(), err)
+	fs.Usage()
+	return err
+}
+
+// run is the scheduler program: it parses args, schedules the workload with
+// each chosen algorithm and writes the results, returning the exit code. Its
+// generate command writes a synthetic workload instead, and its experiment
+// command summarises the algorithms over many workloads.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) > 1 && args[1] == "generate" {
+		return runGenerate(append([]string{args[0] + " generate"}, args[2:]...), stdout, stderr)
+	}
+	if len(args) > 1 && args[1] == "experiment" {
+		return runExperiment(append([]string{args[0] + " experiment"}, args[2:]...), stdout, stderr)
+	}
+	// parseArgs reports its own errors along with the usage text.
+	cfg, err := parseArgs(args, stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	if err != nil {
+		return exitUsage
+	}
+	if cfg.list {
+		listAlgorithms(stdout)
+		return exitOK
+	}
+
+	schedulers := make([]Scheduler, len(cfg.algorithms))
+	for i, name := range cfg.algorithms {
+		if schedulers[i], err = NewScheduler(strings.TrimSpace(name), cfg.params); err != nil {
+			_, _ = fmt.Fprintln(stderr, err)
+			return exitUsage
+		}
+	}
+	if err := checkParameters(cfg.params, schedulers); err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	if err := schedule(cfg, schedulers, stdin, stdout); err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	return exitOK
+}
+
+// schedule loads the workload and writes the runs of schedulers over it.
+func schedule(cfg config, schedulers []Scheduler, stdin io.Reader, stdout io.Writer) (err error) {
+	r := stdin
+	if cfg.workload != "-" {
+		f, err := openWorkload(cfg.workload)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	processes, err := loadProcesses(r)
+	if err != nil {
+		return err
+	}
+	if processes, err = expandPeriodic(processes); err != nil {
+		return err
+	}
+
+	runs := make([]Run, len(schedulers))
+	for i, s := range schedulers {
+		runs[i] = newRun(s, processes)
+		if cfg.window > 0 {
+			runs[i].Windows = throughputWindows(runs[i].Processes, cfg.window)
+		}
+	}
+
+	out := Output{W: stdout}
+	for _, file := range []struct {
+		path, what string
+		w          *io.Writer
+	}{
+		{cfg.output, "output", &out.W},
+		{cfg.slices, "slices", &out.Slices},
+		{cfg.windows, "windows", &out.Windows},
+	} {
+		if file.path == "" {
+			continue
+		}
+		f, createErr := os.Create(file.path)
+		if createErr != nil {
+			return fmt.Errorf("%v: error creating %s file", createErr, file.what)
+		}
+		defer closeOutput(f, file.what, &err)
+		*file.w = f
+	}
+	return writeRuns(cfg.format, out, runs)
+}
+
+// closeOutput closes the output file f, setting *err to any error closing it
+// unless it is already set. Writes may only fail when the file is closed.
+func closeOutput(f *os.File, what string, err *error) {
+	if closeErr := f.Close(); closeErr != nil && *err == nil {
+		*err = fmt.Errorf("%v: error closing %s file", closeErr, what)
+	}
+}
+
+// writeOutput writes with write to the file at path, or to stdout if path is
+// empty.
+func writeOutput(path string, stdout io.Writer, write func(w io.Writer) error) (err error) {
+	if path == "" {
+		return write(stdout)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("%v: error creating output file", err)
+	}
+	defer closeOutput(f, "output", &err)
+	return write(f)
+}
+
+// listAlgorithms writes the registered algorithms and their default
+// parameters.
+func listAlgorithms(w io.Writer) {
+	for _, name := range Algorithms() {
+		s, err := NewScheduler(name, nil)
+		if err != nil {
+			_, _ = fmt.Fprintf(w, "%-12s %v\n", name, err)
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "%-12s %s", name, s.Title())
+		if params := paramFlag(s.Parameters()).String(); params != "" {
+			_, _ = fmt.Fprintf(w, " (%s)", params)
+		}
+		_, _ = fmt.Fprintln(w)
+	}
+}
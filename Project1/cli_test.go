@@ -0,0 +1,256 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const exampleCSV = `1,5,0,2
+2,9,3,1
+3,6,6,3`
+
+func Test_run(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		args       []string
+		stdin      string
+		wantCode   int
+		wantOut    string
+		wantStderr string
+	}{
+		{
+			name:     "list",
+			args:     []string{"-list"},
+			wantCode: exitOK,
+			wantOut:  "rr           Round-robin (quantum=1)\n",
+		},
+		{
+			name:     "help",
+			args:     []string{"-h"},
+			wantCode: exitOK,
+		},
+		{
+			name:       "no workload",
+			wantCode:   exitUsage,
+			wantStderr: "must give a scheduling file to process",
+		},
+		{
+			name:       "bad flag",
+			args:       []string{"-bogus", "-"},
+			wantCode:   exitUsage,
+			wantStderr: "flag provided but not defined: -bogus",
+		},
+		{
+			name:       "bad param",
+			args:       []string{"-param", "quantum", "-"},
+			wantCode:   exitUsage,
+			wantStderr: "expected name=value",
+		},
+		{
+			name:       "unknown param",
+			args:       []string{"-a", "rr,lottery", "-param", "seeed=2", "-"},
+			wantCode:   exitUsage,
+			wantStderr: `unknown parameter "seeed", want one of cpus, energy, little, percore, quantum, seed, slowdown, switch`,
+		},
+		{
+			name:       "unknown algorithm",
+			args:       []string{"-a", "fcfs,nope", "-"},
+			wantCode:   exitUsage,
+			wantStderr: `unknown scheduler: "nope"`,
+		},
+		{
+			name:       "unknown format",
+			args:       []string{"-format", "yaml", "-"},
+			wantCode:   exitUsage,
+			wantStderr: `unknown output format: "yaml"`,
+		},
+		{
+			name:       "bad quantum",
+			args:       []string{"-a", "rr", "-quantum", "0", "-"},
+			wantCode:   exitUsage,
+			wantStderr: "quantum must be positive",
+		},
+		{
+			name:       "negative switch cost",
+			args:       []string{"-switch", "-1", "-"},
+			wantCode:   exitUsage,
+			wantStderr: "switch must not be negative",
+		},
+		{
+			name:     "switch cost",
+			args:     []string{"-a", "fcfs", "-switch", "1", "-format", "csv", "-"},
+			stdin:    exampleCSV,
+			wantCode: exitOK,
+			wantOut: `fcfs,1,2,5,0,0,5,5,0
+fcfs,2,1,9,3,3,12,15,3
+fcfs,3,3,6,6,10,16,22,10
+`,
+		},
+		{
+			name:     "multiprocessor",
+			args:     []string{"-a", "fcfs", "-cpus", "2", "-percore", "-"},
+			stdin:    exampleCSV,
+			wantCode: exitOK,
+			wantOut:  "CPU 0\n|   1   |   3   |\n0\t6\t12\nCPU 1\n|   2   |\n3\t12\n",
+		},
+		{
+			name:     "I/O bursts",
+			args:     []string{"-a", "fcfs", "-"},
+			stdin:    "1,\"cpu:5,io:3,cpu:2\",0,2\n2,cpu:2;io:4;cpu:1,1,1\n3,4,2,3\n",
+			wantCode: exitOK,
+			wantOut:  "I/O\n|   1   |   2   |\n5\t8\t12\n",
+		},
+		{
+			name:       "too many CPUs",
+			args:       []string{"-cpus", "65", "-"},
+			wantCode:   exitUsage,
+			wantStderr: "at most 64 cpus",
+		},
+		{
+			name:       "missing file",
+			args:       []string{"no_such_file.csv"},
+			wantCode:   exitError,
+			wantStderr: "error opening scheduling file",
+		},
+		{
+			name:       "invalid workload",
+			args:       []string{"-"},
+			stdin:      "1,5,0,2\n2,-9,3,1\n3,6\n",
+			wantCode:   exitError,
+			wantStderr: "line 2, column 2 (burst): must not be negative: -9\nline 3, column 3 (arrival): missing field\n",
+		},
+		{
+			name:     "throughput windows",
+			args:     []string{"-a", "fcfs", "-window", "10", "-"},
+			stdin:    exampleCSV,
+			wantCode: exitOK,
+			wantOut:  "| 0-10   |         1 | 0.10/t     |\n| 10-20  |         2 | 0.20/t     |\n",
+		},
+		{
+			name:       "negative window",
+			args:       []string{"-window", "-1", "-"},
+			wantCode:   exitUsage,
+			wantStderr: "window must not be negative",
+		},
+		{
+			name:       "window to csv stdout",
+			args:       []string{"-format", "csv", "-window", "10", "-"},
+			wantCode:   exitUsage,
+			wantStderr: "-window needs the text or json format, or csv with -o or -windows",
+		},
+		{
+			name:       "window to svg",
+			args:       []string{"-format", "svg", "-window", "10", "-windows", "w.csv", "-"},
+			wantCode:   exitUsage,
+			wantStderr: "-window needs the text or json format, or csv with -o or -windows",
+		},
+		{
+			name:     "generate",
+			args:     []string{"generate", "-n", "3", "-burst", "uniform", "-min", "4", "-max", "4", "-pmin", "2", "-pmax", "2"},
+			wantCode: exitOK,
+			wantOut:  "1,4,0,2\n2,4,",
+		},
+		{
+			name:       "generate bad distribution",
+			args:       []string{"generate", "-burst", "normal"},
+			wantCode:   exitUsage,
+			wantStderr: `unknown burst distribution "normal"`,
+		},
+		{
+			name:     "stdin",
+			args:     []string{"-a", "fcfs, rr", "-quantum", "5", "-format", "csv", "-"},
+			stdin:    exampleCSV,
+			wantCode: exitOK,
+			wantOut: `algorithm,id,priority,burst,arrival,wait,turnaround,completion,response
+fcfs,1,2,5,0,0,5,5,0
+fcfs,2,1,9,3,2,11,14,2
+fcfs,3,3,6,6,8,14,20,8
+rr,1,2,5,0,0,5,5,0
+rr,2,1,9,3,7,16,19,2
+rr,3,3,6,6,8,14,20,4
+`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var stdout, stderr bytes.Buffer
+			args := append([]string{"Project1"}, tt.args...)
+			if got := run(args, strings.NewReader(tt.stdin), &stdout, &stderr); got != tt.wantCode {
+				t.Errorf("run() = %v, want %v (stderr %q)", got, tt.wantCode, stderr.String())
+			}
+			if got := stdout.String(); !strings.Contains(got, tt.wantOut) {
+				t.Errorf("run() stdout = %v, want %v", got, tt.wantOut)
+			}
+			if got := stderr.String(); !strings.Contains(got, tt.wantStderr) {
+				t.Errorf("run() stderr = %v, want %v", got, tt.wantStderr)
+			}
+		})
+	}
+}
+
+func Test_run_outputFile(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	workload := filepath.Join(dir, "workload.csv")
+	if err := os.WriteFile(workload, []byte(exampleCSV), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	output := filepath.Join(dir, "out.csv")
+
+	var stdout, stderr bytes.Buffer
+	if got := run([]string{"Project1", "-a", "sjf", "-format", "csv", "-window", "10", "-o", output, workload}, nil, &stdout, &stderr); got != exitOK {
+		t.Fatalf("run() = %v, want %v (stderr %q)", got, exitOK, stderr.String())
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("run() wrote %q to stdout", stdout.String())
+	}
+	for file, want := range map[string]string{
+		output:                                "sjf,3,3,6,6,0,6,12,0\n",
+		filepath.Join(dir, "out_slices.csv"):  "sjf,2,12,20,0\n",
+		filepath.Join(dir, "out_windows.csv"): "sjf,10,20,2,0.2\n",
+	} {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("could not read %v: %v", file, err)
+		}
+		if !strings.Contains(string(b), want) {
+			t.Errorf("%v = %v, want %v", file, string(b), want)
+		}
+	}
+}
+
+func Test_closeOutput(t *testing.T) {
+	t.Parallel()
+	earlier := errors.New("earlier")
+	for _, tt := range []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "reports the close error", want: "error closing output file"},
+		{name: "keeps an earlier error", err: earlier, want: "earlier"},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			f, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Closing it again fails.
+			_ = f.Close()
+			err = tt.err
+			closeOutput(f, "output", &err)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("closeOutput() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
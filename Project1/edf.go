@@ -0,0 +1,42 @@
+package main
+
+import "math"
+
+func init() {
+	Register("edf", func(params Parameters) (Scheduler, error) {
+		m, err := newMachine(params)
+		return edf{m}, err
+	})
+}
+
+// edf is preemptive earliest deadline first: the ready process with the
+// nearest deadline runs, preempting the running process when one with an
+// earlier deadline arrives. Processes without a deadline run last, in order
+// of arrival.
+type edf struct {
+	machine
+}
+
+func (edf) Name() string             { return "edf" }
+func (edf) Title() string            { return "Earliest deadline first" }
+func (s edf) Parameters() Parameters { return s.parameters(Parameters{}) }
+
+func (s edf) Schedule(processes []Process) Result {
+	return s.simulate(processes, orderBy(earlierDeadline, true))
+}
+
+// deadline returns the deadline of t, or the end of time if it has none.
+func deadline(t *task) int64 {
+	if t.Deadline <= 0 {
+		return math.MaxInt64
+	}
+	return t.Deadline
+}
+
+// earlierDeadline orders tasks by deadline, then arrival, then workload order.
+func earlierDeadline(a, b *task) bool {
+	if deadline(a) != deadline(b) {
+		return deadline(a) < deadline(b)
+	}
+	return byArrival(a, b)
+}
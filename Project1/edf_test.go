@@ -0,0 +1,49 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEDF_Schedule(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 4, Deadline: 10},
+		{ProcessID: 2, BurstDuration: 2, ArrivalTime: 1, Deadline: 4},
+		{ProcessID: 3, BurstDuration: 3, ArrivalTime: 2, Deadline: 6},
+		{ProcessID: 4, BurstDuration: 1, ArrivalTime: 3},
+		{ProcessID: 5, BurstDuration: 2, ArrivalTime: 4, Deadline: 8},
+	}
+	got := edf{}.Schedule(processes)
+
+	wantGantt := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 1},
+		{PID: 2, Start: 1, Stop: 3},
+		{PID: 3, Start: 3, Stop: 6},
+		{PID: 5, Start: 6, Stop: 8},
+		{PID: 1, Start: 8, Stop: 11},
+		{PID: 4, Start: 11, Stop: 12},
+	}
+	if !reflect.DeepEqual(got.Gantt, wantGantt) {
+		t.Errorf("Gantt = %v, want %v", got.Gantt, wantGantt)
+	}
+
+	wantLateness := []int64{1, -1, 0, 0, 0}
+	for i, row := range got.Processes {
+		if row.Lateness != wantLateness[i] || row.Missed != (wantLateness[i] > 0) {
+			t.Errorf("process %d lateness = %d missed = %v, want %d", row.ProcessID, row.Lateness, row.Missed, wantLateness[i])
+		}
+	}
+	wantDeadlines := &DeadlineStats{Processes: 4, Missed: 1, MissRatio: 0.25, TotalLateness: 1, MaxLateness: 1}
+	if !reflect.DeepEqual(got.Deadlines, wantDeadlines) {
+		t.Errorf("Deadlines = %+v, want %+v", got.Deadlines, wantDeadlines)
+	}
+}
+
+func Test_deadlineStats_none(t *testing.T) {
+	t.Parallel()
+	got := fcfs{}.Schedule([]Process{{ProcessID: 1, BurstDuration: 2}})
+	if got.Deadlines != nil {
+		t.Errorf("Deadlines = %+v, want nil", got.Deadlines)
+	}
+}
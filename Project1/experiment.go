@@ -0,0 +1,282 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+type (
+	// Summary describes a metric of one algorithm across the workloads of an
+	// experiment.
+	Summary struct {
+		Metric string  `json:"metric"`
+		Mean   float64 `json:"mean"`
+		StdDev float64 `json:"std_dev"`
+		P5     float64 `json:"p5"`
+		Median float64 `json:"median"`
+		P95    float64 `json:"p95"`
+		// Low and High bound the confidence interval of the mean.
+		Low  float64 `json:"low"`
+		High float64 `json:"high"`
+	}
+
+	// AlgorithmSummary is how one algorithm did across an experiment.
+	AlgorithmSummary struct {
+		Algorithm string    `json:"algorithm"`
+		Metrics   []Summary `json:"metrics"`
+	}
+
+	// experimentMetric is a figure of a result summarised by an experiment,
+	// shown to precision decimal places.
+	experimentMetric struct {
+		name      string
+		precision int
+		value     func(Result) float64
+	}
+)
+
+// experimentMetrics are the figures an experiment summarises, in order.
+var experimentMetrics = []experimentMetric{
+	{name: "wait", precision: 2, value: func(res Result) float64 { return res.Stats.AverageWait }},
+	{name: "turnaround", precision: 2, value: func(res Result) float64 { return res.Stats.AverageTurnaround }},
+	{name: "throughput", precision: 4, value: func(res Result) float64 { return res.Stats.Throughput }},
+}
+
+// experiment runs every scheduler over every workload and summarises each
+// metric per scheduler, with confidence intervals at the given level.
+func experiment(schedulers []Scheduler, workloads [][]Process, level float64) []AlgorithmSummary {
+	summaries := make([]AlgorithmSummary, len(schedulers))
+	for i, s := range schedulers {
+		samples := make([][]float64, len(experimentMetrics))
+		for _, processes := range workloads {
+			res := s.Schedule(processes)
+			for j, m := range experimentMetrics {
+				samples[j] = append(samples[j], m.value(res))
+			}
+		}
+		summaries[i].Algorithm = s.Name()
+		for j, m := range experimentMetrics {
+			sum := summarise(samples[j], level)
+			sum.Metric = m.name
+			summaries[i].Metrics = append(summaries[i].Metrics, sum)
+		}
+	}
+	return summaries
+}
+
+// summarise describes a sample of at least one value. The confidence
+// interval of its mean uses the normal approximation, which suits the
+// hundreds of workloads of a typical experiment.
+func summarise(sample []float64, level float64) Summary {
+	var (
+		n      = float64(len(sample))
+		sorted = append([]float64(nil), sample...)
+		sum    Summary
+	)
+	sort.Float64s(sorted)
+	for _, x := range sample {
+		sum.Mean += x
+	}
+	sum.Mean /= n
+	if len(sample) > 1 {
+		var squares float64
+		for _, x := range sample {
+			squares += (x - sum.Mean) * (x - sum.Mean)
+		}
+		sum.StdDev = math.Sqrt(squares / (n - 1))
+	}
+	sum.P5 = percentile(sorted, 5)
+	sum.Median = percentile(sorted, 50)
+	sum.P95 = percentile(sorted, 95)
+	half := math.Sqrt2 * math.Erfinv(level) * sum.StdDev / math.Sqrt(n)
+	sum.Low, sum.High = sum.Mean-half, sum.Mean+half
+	return sum
+}
+
+// percentile returns the pth percentile of sorted, interpolating linearly
+// between the values either side of it.
+func percentile(sorted []float64, p float64) float64 {
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(rank)
+	if lower+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[lower] + (rank-float64(lower))*(sorted[lower+1]-sorted[lower])
+}
+
+// writeExperimentText writes the summaries as a table.
+func writeExperimentText(w io.Writer, workloads int, level float64, summaries []AlgorithmSummary) {
+	_, _ = fmt.Fprintf(w, "Experiment: %d workloads\n", workloads)
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Algorithm", "Metric", "Mean", "Std dev", "P5", "Median", "P95", fmt.Sprintf("%g%% CI", 100*level)})
+	table.SetAutoMergeCells(true)
+	table.SetRowLine(true)
+	for _, s := range summaries {
+		for i, sum := range s.Metrics {
+			format := func(f float64) string {
+				return strconv.FormatFloat(f, 'f', experimentMetrics[i].precision, 64)
+			}
+			table.Append([]string{
+				s.Algorithm,
+				sum.Metric,
+				format(sum.Mean),
+				format(sum.StdDev),
+				format(sum.P5),
+				format(sum.Median),
+				format(sum.P95),
+				format(sum.Low) + " - " + format(sum.High),
+			})
+		}
+	}
+	table.Render()
+}
+
+// writeExperimentCSV writes one row per algorithm and metric.
+func writeExperimentCSV(w io.Writer, workloads int, level float64, summaries []AlgorithmSummary) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"algorithm", "metric", "workloads", "mean", "std_dev", "p5", "median", "p95", "confidence", "ci_low", "ci_high"})
+	format := func(f float64) string { return strconv.FormatFloat(f, 'g', -1, 64) }
+	for _, s := range summaries {
+		for _, sum := range s.Metrics {
+			_ = cw.Write([]string{
+				s.Algorithm,
+				sum.Metric,
+				strconv.Itoa(workloads),
+				format(sum.Mean),
+				format(sum.StdDev),
+				format(sum.P5),
+				format(sum.Median),
+				format(sum.P95),
+				format(level),
+				format(sum.Low),
+				format(sum.High),
+			})
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("%w: writing CSV", err)
+	}
+	return nil
+}
+
+// runExperiment is the experiment command: it parses args, args[0] being the
+// command name, runs the chosen algorithms over the workload files they name,
+// or else over generated workloads, and writes the summaries, returning the
+// exit code.
+func runExperiment(args []string, stdout, stderr io.Writer) int {
+	var (
+		params = make(Parameters)
+		spec   workloadSpec
+		fs     = flag.NewFlagSet(args[0], flag.ContinueOnError)
+
+		algorithms = fs.String("a", "all", `comma separated algorithms to run, or "all"`)
+		runs       = fs.Int("runs", 100, "number of workloads to generate, seeded from -seed up, when no workload files are given")
+		level      = fs.Float64("confidence", 0.95, "confidence level of the intervals of the means")
+		format     = fs.String("format", "text", "output format: text or csv")
+		output     = fs.String("o", "", "write output to `file` instead of stdout")
+		setParams  = schedulerFlags(fs, params)
+	)
+	workloadFlags(fs, &spec)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(stderr, "Usage: %s [flags] [workload.csv ...]\n\n", fs.Name())
+		_, _ = fmt.Fprintln(stderr, "Summarises how the algorithms do across many workloads, generated unless given.")
+		_, _ = fmt.Fprintf(stderr, "Algorithms: %s\n\nFlags:\n", strings.Join(Algorithms(), ", "))
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	setParams()
+	names := Algorithms()
+	if *algorithms != "all" {
+		names = strings.Split(*algorithms, ",")
+	}
+	schedulers := make([]Scheduler, len(names))
+	for i, name := range names {
+		var err error
+		if schedulers[i], err = NewScheduler(strings.TrimSpace(name), params); err != nil {
+			_, _ = fmt.Fprintln(stderr, err)
+			return exitUsage
+		}
+	}
+	err := checkParameters(params, schedulers)
+	switch {
+	case err != nil:
+	case *format != "text" && *format != "csv":
+		err = fmt.Errorf("%w: %q", ErrUnknownFormat, *format)
+	case !(*level > 0 && *level < 1):
+		err = fmt.Errorf("%w: confidence must be between 0 and 1, got %g", ErrInvalidArgs, *level)
+	case fs.NArg() == 0 && *runs <= 0:
+		err = fmt.Errorf("%w: runs must be positive, got %d", ErrInvalidArgs, *runs)
+	case fs.NArg() == 0:
+		err = spec.validate()
+	}
+	if err != nil {
+		_ = usageError(fs, err)
+		return exitUsage
+	}
+
+	workloads, err := experimentWorkloads(fs.Args(), spec, *runs)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	summaries := experiment(schedulers, workloads, *level)
+
+	err = writeOutput(*output, stdout, func(w io.Writer) error {
+		if *format == "csv" {
+			return writeExperimentCSV(w, len(workloads), *level, summaries)
+		}
+		writeExperimentText(w, len(workloads), *level, summaries)
+		return nil
+	})
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	return exitOK
+}
+
+// experimentWorkloads loads the workload files, or when there are none
+// generates runs workloads from spec with successive seeds.
+func experimentWorkloads(files []string, spec workloadSpec, runs int) ([][]Process, error) {
+	if len(files) == 0 {
+		workloads := make([][]Process, runs)
+		for i := range workloads {
+			workloads[i] = spec.generate()
+			spec.seed++
+		}
+		return workloads, nil
+	}
+	workloads := make([][]Process, len(files))
+	for i, file := range files {
+		f, err := openWorkload(file)
+		if err != nil {
+			return nil, err
+		}
+		processes, err := loadProcesses(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		if workloads[i], err = expandPeriodic(processes); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+	}
+	return workloads, nil
+}
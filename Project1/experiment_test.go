@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_summarise(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		sample []float64
+		want   Summary
+	}{
+		{
+			name:   "single value",
+			sample: []float64{4},
+			want:   Summary{Mean: 4, P5: 4, Median: 4, P95: 4, Low: 4, High: 4},
+		},
+		{
+			name:   "spread",
+			sample: []float64{5, 1, 4, 2, 3},
+			want: Summary{
+				Mean: 3, StdDev: math.Sqrt(2.5),
+				P5: 1.2, Median: 3, P95: 4.8,
+				Low:  3 - 1.959963984540054*math.Sqrt(2.5)/math.Sqrt(5),
+				High: 3 + 1.959963984540054*math.Sqrt(2.5)/math.Sqrt(5),
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := summarise(tt.sample, 0.95)
+			for _, f := range []struct {
+				name      string
+				got, want float64
+			}{
+				{"Mean", got.Mean, tt.want.Mean},
+				{"StdDev", got.StdDev, tt.want.StdDev},
+				{"P5", got.P5, tt.want.P5},
+				{"Median", got.Median, tt.want.Median},
+				{"P95", got.P95, tt.want.P95},
+				{"Low", got.Low, tt.want.Low},
+				{"High", got.High, tt.want.High},
+			} {
+				if math.Abs(f.got-f.want) > 1e-9 {
+					t.Errorf("%s = %v, want %v", f.name, f.got, f.want)
+				}
+			}
+		})
+	}
+}
+
+func Test_experiment(t *testing.T) {
+	t.Parallel()
+	processes, err := loadProcesses(strings.NewReader(exampleCSV))
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := testSpec("exponential", 10)
+	workloads := [][]Process{processes, processes, spec.generate()}
+	got := experiment([]Scheduler{fcfs{}, sjf{}}, workloads, 0.95)
+	if len(got) != 2 || got[0].Algorithm != "fcfs" || got[1].Algorithm != "sjf" {
+		t.Fatalf("experiment() = %+v, want fcfs then sjf", got)
+	}
+	for _, s := range got {
+		if len(s.Metrics) != len(experimentMetrics) {
+			t.Fatalf("%s has %d metrics, want %d", s.Algorithm, len(s.Metrics), len(experimentMetrics))
+		}
+		for i, m := range s.Metrics {
+			if m.Metric != experimentMetrics[i].name {
+				t.Errorf("%s metric %d = %q, want %q", s.Algorithm, i, m.Metric, experimentMetrics[i].name)
+			}
+		}
+	}
+	// Two of the three workloads are the example, so its figures are the
+	// median.
+	if wait := got[0].Metrics[0].Median; wait != 10.0/3 {
+		t.Errorf("fcfs median wait = %v, want %v", wait, 10.0/3)
+	}
+	if got[1].Metrics[0].Mean > got[0].Metrics[0].Mean {
+		t.Errorf("sjf mean wait %v above fcfs %v", got[1].Metrics[0].Mean, got[0].Metrics[0].Mean)
+	}
+}
+
+func Test_runExperiment(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	workload := filepath.Join(dir, "workload.csv")
+	if err := os.WriteFile(workload, []byte(exampleCSV), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantOut    string
+		wantStderr string
+	}{
+		{
+			name:     "generated",
+			args:     []string{"-a", "fcfs,rr", "-runs", "30"},
+			wantCode: exitOK,
+			wantOut:  "Experiment: 30 workloads",
+		},
+		{
+			name:     "files as csv",
+			args:     []string{"-a", "fcfs", "-format", "csv", workload, workload},
+			wantCode: exitOK,
+			wantOut: `algorithm,metric,workloads,mean,std_dev,p5,median,p95,confidence,ci_low,ci_high
+fcfs,wait,2,3.3333333333333335,0,3.3333333333333335,3.3333333333333335,3.3333333333333335,0.95,3.3333333333333335,3.3333333333333335
+fcfs,turnaround,2,10,0,10,10,10,0.95,10,10
+fcfs,throughput,2,0.15,0,0.15,0.15,0.15,0.95,0.15,0.15
+`,
+		},
+		{
+			name:       "bad confidence",
+			args:       []string{"-confidence", "95"},
+			wantCode:   exitUsage,
+			wantStderr: "confidence must be between 0 and 1",
+		},
+		{
+			name:       "bad workload spec",
+			args:       []string{"-rate", "0"},
+			wantCode:   exitUsage,
+			wantStderr: "arrival rate must be positive",
+		},
+		{
+			name:       "unknown algorithm",
+			args:       []string{"-a", "nope"},
+			wantCode:   exitUsage,
+			wantStderr: `unknown scheduler: "nope"`,
+		},
+		{
+			name:       "unknown param",
+			args:       []string{"-a", "cfs", "-param", "quantum0=3"},
+			wantCode:   exitUsage,
+			wantStderr: `unknown parameter "quantum0"`,
+		},
+		{
+			name:       "missing file",
+			args:       []string{filepath.Join(dir, "missing.csv")},
+			wantCode:   exitError,
+			wantStderr: "error opening scheduling file",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var stdout, stderr bytes.Buffer
+			args := append([]string{"Project1", "experiment"}, tt.args...)
+			if got := run(args, nil, &stdout, &stderr); got != tt.wantCode {
+				t.Errorf("run() = %v, want %v (stderr %q)", got, tt.wantCode, stderr.String())
+			}
+			if got := stdout.String(); !strings.Contains(got, tt.wantOut) {
+				t.Errorf("run() stdout = %v, want %v", got, tt.wantOut)
+			}
+			if got := stderr.String(); !strings.Contains(got, tt.wantStderr) {
+				t.Errorf("run() stderr = %v, want %v", got, tt.wantStderr)
+			}
+		})
+	}
+}
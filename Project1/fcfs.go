@@ -0,0 +1,33 @@
+package main
+
+import (
+	"io"
+)
+
+func init() {
+	Register("fcfs", func(params Parameters) (Scheduler, error) {
+		m, err := newMachine(params)
+		return fcfs{m}, err
+	})
+}
+
+// fcfs runs processes to completion in the order they arrive.
+type fcfs struct {
+	machine
+}
+
+func (fcfs) Name() string             { return "fcfs" }
+func (fcfs) Title() string            { return "First-come, first-serve" }
+func (s fcfs) Parameters() Parameters { return s.parameters(Parameters{}) }
+
+func (s fcfs) Schedule(processes []Process) Result {
+	return s.simulate(processes, orderBy(byArrival, false))
+}
+
+// FCFSSchedule outputs a schedule of processes in a GANTT chart and a table of timing given:
+// • an output writer
+// • a title for the chart
+// • a slice of processes
+func FCFSSchedule(w io.Writer, title string, processes []Process) {
+	outputResult(w, title, fcfs{}.Schedule(processes))
+}
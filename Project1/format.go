@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+var ErrUnknownFormat = errors.New("unknown output format")
+
+type (
+	// Run is the result of one scheduler over a workload, labelled with the
+	// algorithm that produced it.
+	Run struct {
+		Algorithm  string     `json:"algorithm"`
+		Title      string     `json:"title"`
+		Parameters Parameters `json:"parameters,omitempty"`
+		Result
+	}
+
+	// Output is where a format writes its runs. Slices receives the Gantt
+	// chart, and Windows the windowed throughput, for formats that keep them
+	// apart from the schedule table.
+	Output struct {
+		W       io.Writer
+		Slices  io.Writer
+		Windows io.Writer
+	}
+
+	// formatter writes runs in a single output format.
+	formatter func(out Output, runs []Run) error
+)
+
+// formats are the output formats by name.
+var formats = map[string]formatter{
+	"text": writeText,
+	"json": writeJSON,
+	"csv":  writeCSV,
+}
+
+// formatNames returns the sorted names of the output formats.
+func formatNames() []string {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// newRun runs s over processes.
+func newRun(s Scheduler, processes []Process) Run {
+	return Run{
+		Algorithm:  s.Name(),
+		Title:      s.Title(),
+		Parameters: s.Parameters(),
+		Result:     s.Schedule(processes),
+	}
+}
+
+// writeRuns writes runs to out in the named format.
+func writeRuns(format string, out Output, runs []Run) error {
+	f, ok := formats[format]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+	return f(out, runs)
+}
+
+// writeText writes the Gantt chart and schedule table of every run.
+func writeText(out Output, runs []Run) error {
+	for _, run := range runs {
+		outputResult(out.W, run.Title, run.Result)
+	}
+	return nil
+}
+
+// writeJSON writes runs as an indented JSON array.
+func writeJSON(out Output, runs []Run) error {
+	enc := json.NewEncoder(out.W)
+	enc.SetIndent("", "  ")
+	return enc.Encode(runs)
+}
+
+// writeCSV writes one row per process of every run to out.W, one row per
+// Gantt slice to out.Slices if it is set, and one row per throughput window
+// to out.Windows if it is set.
+func writeCSV(out Output, runs []Run) error {
+	cw := csv.NewWriter(out.W)
+	_ = cw.Write([]string{"algorithm", "id", "priority", "burst", "arrival", "wait", "turnaround", "completion", "response"})
+	for _, run := range runs {
+		for _, row := range run.Processes {
+			_ = cw.Write([]string{
+				run.Algorithm,
+				row.label(),
+				strconv.FormatInt(row.Priority, 10),
+				strconv.FormatInt(row.BurstDuration, 10),
+				strconv.FormatInt(row.ArrivalTime, 10),
+				strconv.FormatInt(row.Wait, 10),
+				strconv.FormatInt(row.Turnaround, 10),
+				strconv.FormatInt(row.Completion, 10),
+				strconv.FormatInt(row.Response, 10),
+			})
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("%w: writing CSV", err)
+	}
+	if out.Slices == nil {
+		return writeWindowsCSV(out.Windows, runs)
+	}
+
+	cw = csv.NewWriter(out.Slices)
+	_ = cw.Write([]string{"algorithm", "pid", "start", "stop", "cpu"})
+	for _, run := range runs {
+		for _, slice := range run.Gantt {
+			_ = cw.Write([]string{
+				run.Algorithm,
+				slice.label(),
+				strconv.FormatInt(slice.Start, 10),
+				strconv.FormatInt(slice.Stop, 10),
+				strconv.Itoa(slice.CPU),
+			})
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("%w: writing slices CSV", err)
+	}
+	return writeWindowsCSV(out.Windows, runs)
+}
+
+// writeWindowsCSV writes one row per throughput window of every run to w, if
+// it is set.
+func writeWindowsCSV(w io.Writer, runs []Run) error {
+	if w == nil {
+		return nil
+	}
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"algorithm", "start", "stop", "completed", "throughput"})
+	for _, run := range runs {
+		for _, window := range run.Windows {
+			_ = cw.Write([]string{
+				run.Algorithm,
+				strconv.FormatInt(window.Start, 10),
+				strconv.FormatInt(window.Stop, 10),
+				strconv.Itoa(window.Completed),
+				strconv.FormatFloat(window.Throughput, 'g', -1, 64),
+			})
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("%w: writing windows CSV", err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestWriteRuns(t *testing.T) {
+	t.Parallel()
+	run := newRun(fcfs{}, exampleProcesses)
+	run.Title = "First-come, First-serve"
+	tests := []struct {
+		name       string
+		format     string
+		wantOut    string
+		wantSlices string
+		wantErr    error
+	}{
+		{
+			name:   "csv",
+			format: "csv",
+			wantOut: `algorithm,id,priority,burst,arrival,wait,turnaround,completion,response
+fcfs,1,2,5,0,0,5,5,0
+fcfs,2,1,9,3,2,11,14,2
+fcfs,3,3,6,6,8,14,20,8
+`,
+			wantSlices: `algorithm,pid,start,stop,cpu
+fcfs,1,0,5,0
+fcfs,2,5,14,0
+fcfs,3,14,20,0
+`,
+		},
+		{
+			name:    "text",
+			format:  "text",
+			wantOut: loadFixture(t, "fcfs_test.txt"),
+		},
+		{
+			name:    "unknown",
+			format:  "yaml",
+			wantErr: ErrUnknownFormat,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var w, slices bytes.Buffer
+			err := writeRuns(tt.format, Output{W: &w, Slices: &slices}, []Run{run})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("writeRuns() error = %v, want %v", err, tt.wantErr)
+			}
+			if got := w.String(); got != tt.wantOut {
+				t.Errorf("writeRuns() = %v, want %v", got, tt.wantOut)
+			}
+			if got := slices.String(); got != tt.wantSlices {
+				t.Errorf("writeRuns() slices = %v, want %v", got, tt.wantSlices)
+			}
+		})
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	t.Parallel()
+	runs := []Run{newRun(fcfs{}, exampleProcesses), newRun(rr{quantum: 2}, exampleProcesses)}
+	var w bytes.Buffer
+	if err := writeRuns("json", Output{W: &w}, runs); err != nil {
+		t.Fatalf("writeRuns() unexpected error: %v", err)
+	}
+	var got []Run
+	if err := json.Unmarshal(w.Bytes(), &got); err != nil {
+		t.Fatalf("could not decode JSON: %v", err)
+	}
+	// An empty parameter set is omitted from the JSON.
+	runs[0].Parameters = nil
+	if !reflect.DeepEqual(got, runs) {
+		t.Errorf("writeRuns() round trip = %+v, want %+v", got, runs)
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidWorkload = errors.New("invalid workload specification")
+
+type (
+	// workloadSpec describes a synthetic workload: how many processes, the
+	// distribution of their bursts, how often they arrive and their range
+	// of priorities.
+	workloadSpec struct {
+		count int
+		seed  int64
+		// burst names the burst distribution, one of burstDistributions.
+		burst string
+		// mean is the mean of exponential bursts, min and max the bounds of
+		// uniform ones.
+		mean     float64
+		min, max int64
+		// short and long are the two modes of bimodal bursts, a longShare
+		// of which are long.
+		short, long float64
+		longShare   float64
+		// rate is the mean number of arrivals per tick of the Poisson
+		// arrival process.
+		rate                     float64
+		priorityMin, priorityMax int64
+	}
+
+	// burstDistribution draws a burst of at least one tick.
+	burstDistribution func(rng *rand.Rand, spec workloadSpec) int64
+)
+
+// burstDistributions are the distributions of generated bursts by name.
+var burstDistributions = map[string]burstDistribution{
+	"exponential": func(rng *rand.Rand, spec workloadSpec) int64 {
+		return atLeastOne(rng.ExpFloat64() * spec.mean)
+	},
+	"uniform": func(rng *rand.Rand, spec workloadSpec) int64 {
+		return spec.min + rng.Int63n(spec.max-spec.min+1)
+	},
+	// bimodal bursts are normally distributed about either mode, with a
+	// standard deviation of a quarter of it.
+	"bimodal": func(rng *rand.Rand, spec workloadSpec) int64 {
+		mode := spec.short
+		if rng.Float64() < spec.longShare {
+			mode = spec.long
+		}
+		return atLeastOne(mode + rng.NormFloat64()*mode/4)
+	},
+}
+
+// burstDistributionNames returns the sorted names of the burst
+// distributions.
+func burstDistributionNames() []string {
+	names := make([]string, 0, len(burstDistributions))
+	for name := range burstDistributions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func atLeastOne(f float64) int64 {
+	if f < 1 {
+		return 1
+	}
+	return int64(math.Round(f))
+}
+
+// validate reports the first setting of s that cannot generate a workload.
+func (s workloadSpec) validate() error {
+	switch {
+	case s.count <= 0:
+		return fmt.Errorf("%w: count must be positive, got %d", ErrInvalidWorkload, s.count)
+	case burstDistributions[s.burst] == nil:
+		return fmt.Errorf("%w: unknown burst distribution %q", ErrInvalidWorkload, s.burst)
+	case s.burst == "exponential" && !(s.mean > 0):
+		return fmt.Errorf("%w: mean burst must be positive, got %g", ErrInvalidWorkload, s.mean)
+	case s.burst == "uniform" && (s.min < 1 || s.max < s.min):
+		return fmt.Errorf("%w: uniform bursts need 1 <= min <= max, got %d and %d", ErrInvalidWorkload, s.min, s.max)
+	case s.burst == "bimodal" && !(s.short > 0 && s.long > 0):
+		return fmt.Errorf("%w: bimodal modes must be positive, got %g and %g", ErrInvalidWorkload, s.short, s.long)
+	case s.burst == "bimodal" && !(s.longShare >= 0 && s.longShare <= 1):
+		return fmt.Errorf("%w: long share must be between 0 and 1, got %g", ErrInvalidWorkload, s.longShare)
+	case !(s.rate > 0):
+		return fmt.Errorf("%w: arrival rate must be positive, got %g", ErrInvalidWorkload, s.rate)
+	case s.priorityMin < minPriority || s.priorityMax > maxPriority || s.priorityMax < s.priorityMin:
+		return fmt.Errorf("%w: priorities need %d <= min <= max <= %d, got %d and %d", ErrInvalidWorkload, minPriority, maxPriority, s.priorityMin, s.priorityMax)
+	}
+	return nil
+}
+
+// generate draws the workload described by s, which must be valid. The same
+// spec always generates the same workload. The first process arrives at
+// time 0 and the gaps between arrivals are exponentially distributed, making
+// arrivals a Poisson process.
+func (s workloadSpec) generate() []Process {
+	var (
+		rng       = rand.New(rand.NewSource(s.seed))
+		draw      = burstDistributions[s.burst]
+		processes = make([]Process, s.count)
+		now       float64
+	)
+	for i := range processes {
+		if i > 0 {
+			now += rng.ExpFloat64() / s.rate
+		}
+		processes[i] = Process{
+			ProcessID:     int64(i + 1),
+			BurstDuration: draw(rng, s),
+			ArrivalTime:   int64(now),
+			Priority:      s.priorityMin + rng.Int63n(s.priorityMax-s.priorityMin+1),
+		}
+	}
+	return processes
+}
+
+// writeProcesses writes processes as a workload CSV that loadProcesses reads
+// back: ID, burst, arrival and priority.
+func writeProcesses(w io.Writer, processes []Process) error {
+	cw := csv.NewWriter(w)
+	for _, p := range processes {
+		if err := cw.Write([]string{
+			strconv.FormatInt(p.ProcessID, 10),
+			strconv.FormatInt(p.BurstDuration, 10),
+			strconv.FormatInt(p.ArrivalTime, 10),
+			strconv.FormatInt(p.Priority, 10),
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// workloadFlags defines on fs the flags that describe a synthetic workload,
+// setting the fields of spec.
+func workloadFlags(fs *flag.FlagSet, spec *workloadSpec) {
+	fs.IntVar(&spec.count, "n", 20, "number of processes")
+	fs.Int64Var(&spec.seed, "seed", 1, "random seed; the same seed and flags generate the same workload")
+	fs.StringVar(&spec.burst, "burst", "exponential", "burst distribution: "+strings.Join(burstDistributionNames(), ", "))
+	fs.Float64Var(&spec.mean, "mean", 5, "mean of exponential bursts")
+	fs.Int64Var(&spec.min, "min", 1, "shortest uniform burst")
+	fs.Int64Var(&spec.max, "max", 10, "longest uniform burst")
+	fs.Float64Var(&spec.short, "short", 3, "short mode of bimodal bursts")
+	fs.Float64Var(&spec.long, "long", 20, "long mode of bimodal bursts")
+	fs.Float64Var(&spec.longShare, "longshare", 0.2, "fraction of bimodal bursts that are long")
+	fs.Float64Var(&spec.rate, "rate", 0.2, "mean arrivals per tick")
+	fs.Int64Var(&spec.priorityMin, "pmin", 1, "highest priority (lowest number)")
+	fs.Int64Var(&spec.priorityMax, "pmax", 10, "lowest priority (highest number)")
+}
+
+// runGenerate is the generate command: it parses args, args[0] being the
+// command name, and writes the workload they describe, returning the exit
+// code.
+func runGenerate(args []string, stdout, stderr io.Writer) int {
+	var (
+		spec   workloadSpec
+		output string
+		fs     = flag.NewFlagSet(args[0], flag.ContinueOnError)
+	)
+	workloadFlags(fs, &spec)
+	fs.StringVar(&output, "o", "", "write the workload to `file` instead of stdout")
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(stderr, "Usage: %s [flags]\n\n", fs.Name())
+		_, _ = fmt.Fprintln(stderr, "Generates a synthetic workload CSV with Poisson arrivals.")
+		_, _ = fmt.Fprintln(stderr, "\nFlags:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if fs.NArg() != 0 {
+		_ = usageError(fs, fmt.Errorf("%w: unexpected argument %q", ErrInvalidArgs, fs.Arg(0)))
+		return exitUsage
+	}
+	if err := spec.validate(); err != nil {
+		_ = usageError(fs, err)
+		return exitUsage
+	}
+
+	err := writeOutput(output, stdout, func(w io.Writer) error {
+		return writeProcesses(w, spec.generate())
+	})
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	return exitOK
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// testSpec is a valid spec of n processes with the given burst distribution.
+func testSpec(burst string, n int) workloadSpec {
+	return workloadSpec{
+		count: n, seed: 1, burst: burst,
+		mean: 5, min: 2, max: 8, short: 3, long: 20, longShare: 0.25,
+		rate: 0.5, priorityMin: 3, priorityMax: 7,
+	}
+}
+
+func Test_workloadSpec_generate(t *testing.T) {
+	t.Parallel()
+	const n = 20000
+	tests := []struct {
+		burst    string
+		min, max int64
+		mean     float64
+	}{
+		{burst: "exponential", min: 1, max: math.MaxInt64, mean: 5},
+		{burst: "uniform", min: 2, max: 8, mean: 5},
+		{burst: "bimodal", min: 1, max: math.MaxInt64, mean: 0.75*3 + 0.25*20},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.burst, func(t *testing.T) {
+			t.Parallel()
+			spec := testSpec(tt.burst, n)
+			got := spec.generate()
+			if len(got) != n {
+				t.Fatalf("generated %d processes, want %d", len(got), n)
+			}
+			if again := spec.generate(); !reflect.DeepEqual(got, again) {
+				t.Error("the same seed generated different workloads")
+			}
+			spec.seed = 2
+			if other := spec.generate(); reflect.DeepEqual(got, other) {
+				t.Error("different seeds generated the same workload")
+			}
+
+			var total float64
+			for i, p := range got {
+				if p.ProcessID != int64(i+1) {
+					t.Fatalf("process %d has ID %d", i, p.ProcessID)
+				}
+				if p.BurstDuration < tt.min || p.BurstDuration > tt.max {
+					t.Fatalf("process %d burst %d outside [%d, %d]", p.ProcessID, p.BurstDuration, tt.min, tt.max)
+				}
+				if p.Priority < 3 || p.Priority > 7 {
+					t.Fatalf("process %d priority %d outside [3, 7]", p.ProcessID, p.Priority)
+				}
+				if i > 0 && p.ArrivalTime < got[i-1].ArrivalTime {
+					t.Fatalf("process %d arrives at %d, before %d", p.ProcessID, p.ArrivalTime, got[i-1].ArrivalTime)
+				}
+				total += float64(p.BurstDuration)
+			}
+			if got[0].ArrivalTime != 0 {
+				t.Errorf("first arrival = %d, want 0", got[0].ArrivalTime)
+			}
+			if mean := total / n; math.Abs(mean-tt.mean) > 0.05*tt.mean {
+				t.Errorf("mean burst = %.2f, want about %.2f", mean, tt.mean)
+			}
+			if rate := float64(n-1) / float64(got[n-1].ArrivalTime); math.Abs(rate-0.5) > 0.025 {
+				t.Errorf("arrival rate = %.3f, want about 0.5", rate)
+			}
+		})
+	}
+}
+
+func Test_workloadSpec_validate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		modify  func(s *workloadSpec)
+		wantErr bool
+	}{
+		{name: "valid", modify: func(s *workloadSpec) {}},
+		{name: "no processes", modify: func(s *workloadSpec) { s.count = 0 }, wantErr: true},
+		{name: "unknown distribution", modify: func(s *workloadSpec) { s.burst = "normal" }, wantErr: true},
+		{name: "zero mean", modify: func(s *workloadSpec) { s.mean = 0 }, wantErr: true},
+		{name: "zero mean unused", modify: func(s *workloadSpec) { s.burst, s.mean = "uniform", 0 }},
+		{name: "uniform min above max", modify: func(s *workloadSpec) { s.burst, s.min = "uniform", 9 }, wantErr: true},
+		{name: "uniform zero min", modify: func(s *workloadSpec) { s.burst, s.min = "uniform", 0 }, wantErr: true},
+		{name: "bimodal zero mode", modify: func(s *workloadSpec) { s.burst, s.long = "bimodal", 0 }, wantErr: true},
+		{name: "bimodal share above one", modify: func(s *workloadSpec) { s.burst, s.longShare = "bimodal", 1.5 }, wantErr: true},
+		{name: "zero rate", modify: func(s *workloadSpec) { s.rate = 0 }, wantErr: true},
+		{name: "priorities reversed", modify: func(s *workloadSpec) { s.priorityMin = 8 }, wantErr: true},
+		{name: "priority out of range", modify: func(s *workloadSpec) { s.priorityMax = 51 }, wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			spec := testSpec("exponential", 10)
+			tt.modify(&spec)
+			err := spec.validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidWorkload) {
+				t.Errorf("validate() error = %v, want %v", err, ErrInvalidWorkload)
+			}
+		})
+	}
+}
+
+func Test_writeProcesses(t *testing.T) {
+	t.Parallel()
+	want := testSpec("bimodal", 50).generate()
+	var buf bytes.Buffer
+	if err := writeProcesses(&buf, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := loadProcesses(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("loadProcesses() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"math"
+	"math/bits"
+)
+
+func init() {
+	Register("hrrn", func(params Parameters) (Scheduler, error) {
+		m, err := newMachine(params)
+		return hrrn{m}, err
+	})
+}
+
+// hrrn is non-preemptive highest response ratio next: whenever the CPU is
+// free the ready process with the highest (wait + burst) / burst runs to
+// completion. Waiting raises a process's ratio, so long jobs cannot starve
+// as they can under shortest-job-first.
+type hrrn struct {
+	machine
+}
+
+func (hrrn) Name() string             { return "hrrn" }
+func (hrrn) Title() string            { return "Highest response ratio next" }
+func (s hrrn) Parameters() Parameters { return s.parameters(Parameters{}) }
+
+func (s hrrn) Schedule(processes []Process) Result {
+	return s.simulate(processes, func() policy { return &hrrnPolicy{} })
+}
+
+// hrrnPolicy is the simulation state of an hrrn.
+type hrrnPolicy struct {
+	ready ratioTournament
+}
+
+func (p *hrrnPolicy) push(t *task, now int64)      { p.ready.add(t, now) }
+func (p *hrrnPolicy) empty() bool                  { return p.ready.queued == 0 }
+func (p *hrrnPolicy) ran(*task, int64, bool)       {}
+func (p *hrrnPolicy) pop(now int64) (*task, int64) { return p.ready.take(now), 0 }
+
+// ratioLead returns how far a's response ratio leads b's at now, scaled by
+// both bursts so as to compare the fractions without dividing, and whether
+// the lead is negative. The products are taken to 128 bits, hi and lo, so
+// long bursts cannot overflow them.
+func ratioLead(a, b *task, now int64) (hi, lo uint64, negative bool) {
+	pHi, pLo := bits.Mul64(uint64(now-a.ArrivalTime+a.BurstDuration), uint64(b.BurstDuration))
+	qHi, qLo := bits.Mul64(uint64(now-b.ArrivalTime+b.BurstDuration), uint64(a.BurstDuration))
+	if pHi < qHi || pHi == qHi && pLo < qLo {
+		pHi, pLo, qHi, qLo, negative = qHi, qLo, pHi, pLo, true
+	}
+	lo, borrow := bits.Sub64(pLo, qLo, 0)
+	hi, _ = bits.Sub64(pHi, qHi, borrow)
+	return hi, lo, negative
+}
+
+// higherRatio reports whether a's response ratio is higher than b's at now.
+func higherRatio(a, b *task, now int64) bool {
+	hi, lo, negative := ratioLead(a, b, now)
+	return !negative && hi|lo != 0
+}
+
+// ratioTournament is a tournament tree of the ready tasks in slots, each node
+// holding the winner of the tasks below it: the highest response ratio, the
+// earliest queued on a tie. As ratios grow at different rates a node's
+// winner can change with time alone, so each node also holds when its winner
+// next loses to the other side, and the tree is brought up to date at each
+// add or take by replaying only the nodes whose winners have changed. Time
+// must not go backwards.
+type ratioTournament struct {
+	// The leaves are the slots, nodes size to 2*size-1, and the children of
+	// node i are 2i and 2i+1.
+	size   int
+	tasks  []*task
+	seq    []int64
+	free   []int
+	queued int
+	next   int64
+	// winner is the slot that wins at each node, -1 for none, and soonest
+	// the first time the winner of the node or of one below it stops
+	// beating the loser.
+	winner  []int
+	soonest []int64
+}
+
+// add queues t at now.
+func (tr *ratioTournament) add(t *task, now int64) {
+	tr.update(now)
+	if len(tr.free) == 0 {
+		tr.grow(now)
+	}
+	slot := tr.free[len(tr.free)-1]
+	tr.free = tr.free[:len(tr.free)-1]
+	tr.tasks[slot], tr.seq[slot] = t, tr.next
+	tr.next++
+	tr.queued++
+	tr.replay(tr.size+slot, now)
+}
+
+// take removes and returns the winner at now. There must be a ready task.
+func (tr *ratioTournament) take(now int64) *task {
+	tr.update(now)
+	slot := tr.winner[1]
+	t := tr.tasks[slot]
+	tr.tasks[slot] = nil
+	tr.free = append(tr.free, slot)
+	tr.queued--
+	tr.replay(tr.size+slot, now)
+	return t
+}
+
+// replay plays the matches from leaf up to the root again at now.
+func (tr *ratioTournament) replay(leaf int, now int64) {
+	tr.winner[leaf] = -1
+	if t := tr.tasks[leaf-tr.size]; t != nil {
+		tr.winner[leaf] = leaf - tr.size
+	}
+	for node := leaf / 2; node > 0; node /= 2 {
+		tr.play(node, now)
+	}
+}
+
+// update plays again the matches whose winners have changed by now.
+func (tr *ratioTournament) update(now int64) {
+	if tr.size > 0 && tr.soonest[1] <= now {
+		tr.updateNode(1, now)
+	}
+}
+
+func (tr *ratioTournament) updateNode(node int, now int64) {
+	if node >= tr.size || tr.soonest[node] > now {
+		return
+	}
+	tr.updateNode(2*node, now)
+	tr.updateNode(2*node+1, now)
+	tr.play(node, now)
+}
+
+// play decides the match at node at now from the winners of its children.
+func (tr *ratioTournament) play(node int, now int64) {
+	l, r := tr.winner[2*node], tr.winner[2*node+1]
+	lost := int64(math.MaxInt64)
+	switch {
+	case l < 0:
+		tr.winner[node] = r
+	case r < 0:
+		tr.winner[node] = l
+	default:
+		if !tr.beats(l, r, now) {
+			l, r = r, l
+		}
+		tr.winner[node] = l
+		lost = tr.loses(l, r, now)
+	}
+	tr.soonest[node] = min64(lost, min64(tr.soonest[2*node], tr.soonest[2*node+1]))
+}
+
+// beats reports whether slot a wins against slot b at now.
+func (tr *ratioTournament) beats(a, b int, now int64) bool {
+	ta, tb := tr.tasks[a], tr.tasks[b]
+	return higherRatio(ta, tb, now) || !higherRatio(tb, ta, now) && tr.seq[a] < tr.seq[b]
+}
+
+// loses returns the first time after now that slot a, winning at now, stops
+// beating slot b. a's lead changes by the difference of the bursts each tick,
+// so it only ever falls if a's burst is the longer.
+func (tr *ratioTournament) loses(a, b int, now int64) int64 {
+	fall := tr.tasks[a].BurstDuration - tr.tasks[b].BurstDuration
+	if fall <= 0 {
+		return math.MaxInt64
+	}
+	// a loses once the lead, falling by fall a tick, is gone: when it is
+	// negative if a wins ties, else when it is 0.
+	hi, lo, _ := ratioLead(tr.tasks[a], tr.tasks[b], now)
+	if hi >= uint64(fall) {
+		// Not for more than 1<<64 ticks.
+		return math.MaxInt64
+	}
+	ticks, rem := bits.Div64(hi, lo, uint64(fall))
+	if tr.seq[a] < tr.seq[b] || rem != 0 {
+		ticks++
+	}
+	if ticks > math.MaxInt64-uint64(now) {
+		return math.MaxInt64
+	}
+	return now + int64(ticks)
+}
+
+// grow doubles the slots, playing every match again at now.
+func (tr *ratioTournament) grow(now int64) {
+	size := 2 * tr.size
+	if size == 0 {
+		size = 16
+	}
+	tasks, seq := make([]*task, size), make([]int64, size)
+	copy(tasks, tr.tasks)
+	copy(seq, tr.seq)
+	for slot := size - 1; slot >= tr.size; slot-- {
+		tr.free = append(tr.free, slot)
+	}
+	*tr = ratioTournament{
+		size:    size,
+		tasks:   tasks,
+		seq:     seq,
+		free:    tr.free,
+		queued:  tr.queued,
+		next:    tr.next,
+		winner:  make([]int, 2*size),
+		soonest: make([]int64, 2*size),
+	}
+	for node := 2*size - 1; node >= size; node-- {
+		tr.winner[node] = -1
+		if tasks[node-size] != nil {
+			tr.winner[node] = node - size
+		}
+		tr.soonest[node] = math.MaxInt64
+	}
+	for node := size - 1; node > 0; node-- {
+		tr.play(node, now)
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestHRRN_Schedule(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		processes []Process
+		wantGantt []TimeSlice
+	}{
+		{
+			name: "highest ratio when the CPU frees",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 8},
+				{ProcessID: 2, BurstDuration: 4, ArrivalTime: 1},
+				{ProcessID: 3, BurstDuration: 2, ArrivalTime: 2},
+				{ProcessID: 4, BurstDuration: 1, ArrivalTime: 3},
+				{ProcessID: 5, BurstDuration: 6, ArrivalTime: 4},
+			},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 8},
+				{PID: 4, Start: 8, Stop: 9},
+				{PID: 3, Start: 9, Stop: 11},
+				{PID: 2, Start: 11, Stop: 15},
+				{PID: 5, Start: 15, Stop: 21},
+			},
+		},
+		{
+			name: "waiting long job overtakes a shorter one",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 10},
+				{ProcessID: 2, BurstDuration: 3, ArrivalTime: 1},
+				{ProcessID: 3, BurstDuration: 2, ArrivalTime: 9},
+			},
+			// At 10 process 2 has ratio 12/3 = 4 against process 3's 3/2.
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 10},
+				{PID: 2, Start: 10, Stop: 13},
+				{PID: 3, Start: 13, Stop: 15},
+			},
+		},
+		{
+			name: "long bursts",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 3e9},
+				{ProcessID: 2, BurstDuration: 5e9, ArrivalTime: 1},
+				{ProcessID: 3, BurstDuration: 1, ArrivalTime: 1},
+			},
+			// At 3e9 process 3's ratio is about 3e9 against process 2's 1.6,
+			// though the products comparing them overflow 64 bits.
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 3e9},
+				{PID: 3, Start: 3e9, Stop: 3e9 + 1},
+				{PID: 2, Start: 3e9 + 1, Stop: 8e9 + 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := hrrn{}.Schedule(tt.processes)
+			if !reflect.DeepEqual(got.Gantt, tt.wantGantt) {
+				t.Errorf("Gantt = %v, want %v", got.Gantt, tt.wantGantt)
+			}
+		})
+	}
+}
+
+// Test_ratioTournament checks the tournament against scanning the ready
+// tasks, as time passes and tasks come and go, with bursts short and so long
+// that comparing ratios overflows 64 bits.
+func Test_ratioTournament(t *testing.T) {
+	t.Parallel()
+	for _, scale := range []int64{1, 1e12} {
+		var (
+			rng   = rand.New(rand.NewSource(1))
+			tasks = make([]task, 2000)
+			tr    ratioTournament
+			ready []*task
+			now   int64
+		)
+		for i := range tasks {
+			now += rng.Int63n(3) * scale
+			tasks[i] = task{Process: Process{ProcessID: int64(i + 1), ArrivalTime: now, BurstDuration: rng.Int63n(30) * scale}}
+			tr.add(&tasks[i], now)
+			ready = append(ready, &tasks[i])
+			for len(ready) > 0 && rng.Intn(3) == 0 {
+				now += rng.Int63n(5) * scale
+				best := 0
+				for j := range ready {
+					if higherRatio(ready[j], ready[best], now) {
+						best = j
+					}
+				}
+				if got := tr.take(now); got != ready[best] {
+					t.Fatalf("scale %d: take(%d) = process %d, want %d", scale, now, got.ProcessID, ready[best].ProcessID)
+				}
+				ready = append(ready[:best], ready[best+1:]...)
+			}
+		}
+	}
+}
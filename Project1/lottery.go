@@ -0,0 +1,135 @@
+package main
+
+import (
+	"math/bits"
+	"math/rand"
+)
+
+func init() {
+	Register("lottery", newLottery)
+}
+
+// lottery holds a lottery every quantum between the ready processes, each
+// process holding as many tickets as its share.
+type lottery struct {
+	machine
+	quantum int64
+	seed    int64
+}
+
+// newLottery builds a lottery scheduler from the parameters:
+// • quantum: ticks between lotteries (default 1)
+// • seed: random seed, so runs can be reproduced (default 1)
+func newLottery(params Parameters) (Scheduler, error) {
+	quantum, err := params.positive("quantum", 1)
+	if err != nil {
+		return nil, err
+	}
+	m, err := newMachine(params)
+	return lottery{machine: m, quantum: quantum, seed: params.Get("seed", 1)}, err
+}
+
+func (lottery) Name() string  { return "lottery" }
+func (lottery) Title() string { return "Lottery" }
+
+func (s lottery) Parameters() Parameters {
+	return s.parameters(Parameters{"quantum": s.quantum, "seed": s.seed})
+}
+
+func (s lottery) Schedule(processes []Process) Result {
+	rng := rand.New(rand.NewSource(s.seed))
+	res := s.simulate(processes, func() policy {
+		return &lotteryPolicy{lottery: s, rng: rng}
+	})
+	res.Shares = shares(res.Processes)
+	return res
+}
+
+// lotteryPolicy is the simulation state of a lottery.
+type lotteryPolicy struct {
+	lottery
+	rng   *rand.Rand
+	ready ticketTree
+}
+
+func (p *lotteryPolicy) push(t *task, _ int64) { p.ready.add(t) }
+
+// pop draws the winning ticket.
+func (p *lotteryPolicy) pop(int64) (*task, int64) {
+	return p.ready.take(p.rng.Int63n(p.ready.total)), p.quantum
+}
+
+func (p *lotteryPolicy) empty() bool { return p.ready.total == 0 }
+
+func (p *lotteryPolicy) ran(*task, int64, bool) {}
+
+// ticketTree holds the ready tasks in slots, numbered from 1, with a Fenwick
+// tree of the tickets in the slots so that finding the holder of a ticket
+// takes logarithmic time. Slots are reused once their tasks leave.
+type ticketTree struct {
+	tasks []*task
+	// sums[i] is the tickets of the slots from i less its lowest set bit,
+	// exclusive, to i; sums[0] is unused.
+	sums  []int64
+	free  []int
+	total int64
+}
+
+// add gives t a slot.
+func (tr *ticketTree) add(t *task) {
+	if len(tr.free) == 0 {
+		tr.grow()
+	}
+	slot := tr.free[len(tr.free)-1]
+	tr.free = tr.free[:len(tr.free)-1]
+	tr.tasks[slot] = t
+	tr.update(slot, tickets(t.Process))
+}
+
+// take removes and returns the holder of ticket, from 0 to total-1, the
+// tickets being numbered slot by slot. There must be a ready task.
+func (tr *ticketTree) take(ticket int64) *task {
+	slot := 0
+	for step := 1 << (bits.Len(uint(len(tr.sums)-1)) - 1); step > 0; step >>= 1 {
+		if next := slot + step; next < len(tr.sums) && tr.sums[next] <= ticket {
+			slot, ticket = next, ticket-tr.sums[next]
+		}
+	}
+	slot++
+	t := tr.tasks[slot]
+	tr.tasks[slot] = nil
+	tr.free = append(tr.free, slot)
+	tr.update(slot, -tickets(t.Process))
+	return t
+}
+
+// update adds n tickets to slot.
+func (tr *ticketTree) update(slot int, n int64) {
+	tr.total += n
+	for ; slot < len(tr.sums); slot += slot & -slot {
+		tr.sums[slot] += n
+	}
+}
+
+// grow doubles the slots, rebuilding the sums, and frees the new ones.
+func (tr *ticketTree) grow() {
+	n := 2 * len(tr.sums)
+	if n == 0 {
+		n = 16
+	}
+	tasks, sums := make([]*task, n), make([]int64, n)
+	copy(tasks, tr.tasks)
+	for slot := 1; slot < n; slot++ {
+		if tasks[slot] != nil {
+			sums[slot] += tickets(tasks[slot].Process)
+		}
+		if parent := slot + slot&-slot; parent < n {
+			sums[parent] += sums[slot]
+		}
+	}
+	// Free the new slots so the lowest is used first.
+	for slot := n - 1; slot >= len(tr.sums) && slot > 0; slot-- {
+		tr.free = append(tr.free, slot)
+	}
+	tr.tasks, tr.sums = tasks, sums
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestLottery_Schedule(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 4000, Tickets: 3},
+		{ProcessID: 2, BurstDuration: 4000, Tickets: 1},
+	}
+	s, err := NewScheduler("lottery", Parameters{"seed": 42})
+	if err != nil {
+		t.Fatalf("NewScheduler() unexpected error: %v", err)
+	}
+	got := s.Schedule(processes)
+	if again := s.Schedule(processes); !reflect.DeepEqual(got, again) {
+		t.Error("Schedule() is not reproducible with the same seed")
+	}
+	// Over a long run the lottery converges on the ticket ratio.
+	if share := got.Shares[0]; math.Abs(share.Received/share.Expected-1) > 0.05 {
+		t.Errorf("process 1 received %v of the CPU, expected %v", share.Received, share.Expected)
+	}
+	other, err := NewScheduler("lottery", Parameters{"seed": 7})
+	if err != nil {
+		t.Fatalf("NewScheduler() unexpected error: %v", err)
+	}
+	if reflect.DeepEqual(got.Gantt, other.Schedule(processes).Gantt) {
+		t.Error("Schedule() ignored the seed")
+	}
+}
+
+func Test_ticketTree(t *testing.T) {
+	t.Parallel()
+	var (
+		tasks = make([]task, 40)
+		tr    ticketTree
+	)
+	for i := range tasks {
+		tasks[i].index, tasks[i].Tickets = i, int64(i%3+1)
+		tr.add(&tasks[i])
+	}
+	// Tickets are numbered in slot order, which for the first tasks added
+	// is the order they were added: 1, 2 and 3 for each three tasks.
+	if got := tr.take(7); got != &tasks[4] {
+		t.Errorf("take(7) = task %d, want task 4", got.index)
+	}
+	if tr.total != 77 {
+		t.Errorf("total = %d, want 77", tr.total)
+	}
+	// The freed slot is reused.
+	tasks[4].Tickets = 10
+	tr.add(&tasks[4])
+	for ticket, want := range map[int64]int{6: 3, 7: 4, 16: 4, 17: 5, 86: 39} {
+		got := tr.take(ticket)
+		if got.index != want {
+			t.Errorf("take(%d) = task %d, want task %d", ticket, got.index, want)
+		}
+		tr.add(got)
+	}
+}
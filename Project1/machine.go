@@ -0,0 +1,116 @@
+package main
+
+import "fmt"
+
+// maxCPUs is the most CPUs a machine may have, one per bit of an affinity
+// mask.
+const maxCPUs = 64
+
+// maxSlowdown is the slowest a slow CPU may be, keeping the power of a fast
+// CPU, the cube of its speed, in range.
+const maxSlowdown = 1000
+
+// machine is the hardware every scheduler runs on: its CPUs, how their run
+// queues are organised and what a context switch costs.
+type machine struct {
+	switchCost int64
+	cpus       int
+	// perCore gives every CPU its own run queue, balanced by idle CPUs
+	// taking work from the busiest; otherwise all CPUs share one queue.
+	perCore bool
+	// little is the number of slow CPUs, the highest numbered, which take
+	// slowdown ticks for every tick of burst.
+	little   int
+	slowdown int64
+	// energy places work on the CPUs that do it for the least energy.
+	energy bool
+}
+
+// newMachine reads the parameters:
+// • switch: ticks to switch a CPU to a different process (default 0)
+// • cpus: number of CPUs (default 1)
+// • percore: 1 for a run queue per CPU, 0 for one global queue (default 0)
+// • little: number of the CPUs that are slow (default 0)
+// • slowdown: ticks a slow CPU takes per tick of burst (default 2)
+// • energy: 1 for energy-aware placement (default 0)
+func newMachine(params Parameters) (machine, error) {
+	m := machine{
+		switchCost: params.Get("switch", 0),
+		perCore:    params.Get("percore", 0) != 0,
+		energy:     params.Get("energy", 0) != 0,
+	}
+	if m.switchCost < 0 {
+		return machine{}, fmt.Errorf("%w: switch must not be negative, got %d", ErrInvalidParameter, m.switchCost)
+	}
+	cpus, err := params.positive("cpus", 1)
+	if err != nil {
+		return machine{}, err
+	}
+	if cpus > maxCPUs {
+		return machine{}, fmt.Errorf("%w: at most %d cpus, got %d", ErrInvalidParameter, maxCPUs, cpus)
+	}
+	m.cpus = int(cpus)
+	little := params.Get("little", 0)
+	if little < 0 || little > cpus {
+		return machine{}, fmt.Errorf("%w: little must be from 0 to the %d cpus, got %d", ErrInvalidParameter, cpus, little)
+	}
+	m.little = int(little)
+	if m.slowdown, err = params.positive("slowdown", 2); err != nil {
+		return machine{}, err
+	}
+	if m.slowdown > maxSlowdown {
+		return machine{}, fmt.Errorf("%w: slowdown must be at most %d, got %d", ErrInvalidParameter, maxSlowdown, m.slowdown)
+	}
+	return m, nil
+}
+
+// speed returns the burst a CPU gets through per tick, in units of 1/scale
+// of a tick of burst: a fast CPU does scale units a tick and a slow one 1.
+func (m machine) speed(cpu int) int64 {
+	if cpu >= m.cpus-m.little {
+		return 1
+	}
+	return m.scale()
+}
+
+// scale is the number of units in a tick of burst.
+func (m machine) scale() int64 {
+	if m.little == 0 {
+		return 1
+	}
+	return m.slowdown
+}
+
+// power is the energy a CPU uses for every tick it runs, growing with the
+// cube of its speed as voltage rises with frequency; a slow CPU uses 1.
+func (m machine) power(cpu int) int64 {
+	s := m.speed(cpu)
+	return s * s * s
+}
+
+// parameters adds the machine to params where it differs from the default.
+func (m machine) parameters(params Parameters) Parameters {
+	if m.switchCost > 0 {
+		params["switch"] = m.switchCost
+	}
+	if m.cpus > 1 {
+		params["cpus"] = int64(m.cpus)
+	}
+	if m.perCore {
+		params["percore"] = 1
+	}
+	if m.little > 0 {
+		params["little"] = int64(m.little)
+		params["slowdown"] = m.slowdown
+	}
+	if m.energy {
+		params["energy"] = 1
+	}
+	return params
+}
+
+// simulate runs processes on the machine, taking each run queue's policy
+// from newPolicy.
+func (m machine) simulate(processes []Process, newPolicy func() policy) Result {
+	return simulate(processes, newPolicy, m)
+}
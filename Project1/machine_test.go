@@ -0,0 +1,430 @@
+package main
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestMachine_simulate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		scheduler    Scheduler
+		processes    []Process
+		wantGantt    []TimeSlice
+		wantSwitches *SwitchStats
+	}{
+		{
+			name:      "round-robin pays for every turn",
+			scheduler: rr{machine: machine{switchCost: 1}, quantum: 1},
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 2},
+				{ProcessID: 2, BurstDuration: 2},
+			},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 1},
+				{PID: 2, Start: 1, Stop: 2, Switch: true},
+				{PID: 2, Start: 2, Stop: 3},
+				{PID: 1, Start: 3, Stop: 4, Switch: true},
+				{PID: 1, Start: 4, Stop: 5},
+				{PID: 2, Start: 5, Stop: 6, Switch: true},
+				{PID: 2, Start: 6, Stop: 7},
+			},
+			wantSwitches: &SwitchStats{Count: 3, Time: 3, Utilisation: 4.0 / 7},
+		},
+		{
+			name:      "single process never switches",
+			scheduler: rr{machine: machine{switchCost: 1}, quantum: 1},
+			processes: []Process{{ProcessID: 1, BurstDuration: 3}},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 1},
+				{PID: 1, Start: 1, Stop: 2},
+				{PID: 1, Start: 2, Stop: 3},
+			},
+		},
+		{
+			name:      "preempted while switching in",
+			scheduler: sjf{machine{switchCost: 2}},
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 1},
+				{ProcessID: 2, BurstDuration: 5},
+				{ProcessID: 3, BurstDuration: 1, ArrivalTime: 2},
+			},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 1},
+				{PID: 2, Start: 1, Stop: 3, Switch: true},
+				{PID: 3, Start: 3, Stop: 5, Switch: true},
+				{PID: 3, Start: 5, Stop: 6},
+				{PID: 2, Start: 6, Stop: 8, Switch: true},
+				{PID: 2, Start: 8, Stop: 13},
+			},
+			wantSwitches: &SwitchStats{Count: 3, Time: 6, Utilisation: 7.0 / 13},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := tt.scheduler.Schedule(tt.processes)
+			if !reflect.DeepEqual(got.Gantt, tt.wantGantt) {
+				t.Errorf("Gantt = %v, want %v", got.Gantt, tt.wantGantt)
+			}
+			if !reflect.DeepEqual(got.Switches, tt.wantSwitches) {
+				t.Errorf("Switches = %+v, want %+v", got.Switches, tt.wantSwitches)
+			}
+		})
+	}
+}
+
+func TestMachine_multiprocessor(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		scheduler      Scheduler
+		processes      []Process
+		wantGantt      []TimeSlice
+		wantMigrations int
+	}{
+		{
+			name:      "global queue",
+			scheduler: fcfs{machine{cpus: 2}},
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 4},
+				{ProcessID: 2, BurstDuration: 2},
+				{ProcessID: 3, BurstDuration: 3, ArrivalTime: 1},
+				{ProcessID: 4, BurstDuration: 1, ArrivalTime: 1},
+			},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 4},
+				{PID: 2, Start: 0, Stop: 2, CPU: 1},
+				{PID: 3, Start: 2, Stop: 5, CPU: 1},
+				{PID: 4, Start: 4, Stop: 5},
+			},
+		},
+		{
+			name:      "per-core queues placed by load",
+			scheduler: fcfs{machine{cpus: 2, perCore: true}},
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 4},
+				{ProcessID: 2, BurstDuration: 2},
+				{ProcessID: 3, BurstDuration: 3, ArrivalTime: 1},
+				{ProcessID: 4, BurstDuration: 1, ArrivalTime: 1},
+			},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 4},
+				{PID: 2, Start: 0, Stop: 2, CPU: 1},
+				{PID: 4, Start: 2, Stop: 3, CPU: 1},
+				{PID: 3, Start: 3, Stop: 6, CPU: 1},
+			},
+		},
+		{
+			name:      "idle core steals a preempted process",
+			scheduler: rr{machine: machine{cpus: 2, perCore: true}, quantum: 2},
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 6},
+				{ProcessID: 2, BurstDuration: 1},
+				{ProcessID: 3, BurstDuration: 4},
+				{ProcessID: 4, BurstDuration: 4},
+			},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 2},
+				{PID: 2, Start: 0, Stop: 1, CPU: 1},
+				{PID: 4, Start: 1, Stop: 3, CPU: 1},
+				{PID: 3, Start: 2, Stop: 4},
+				{PID: 4, Start: 3, Stop: 5, CPU: 1},
+				{PID: 1, Start: 4, Stop: 6},
+				{PID: 3, Start: 5, Stop: 7, CPU: 1},
+				{PID: 1, Start: 6, Stop: 8},
+			},
+			wantMigrations: 1,
+		},
+		{
+			name:      "affinity",
+			scheduler: fcfs{machine{cpus: 2}},
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 3, Affinity: 1},
+				{ProcessID: 2, BurstDuration: 3, Affinity: 1},
+				{ProcessID: 3, BurstDuration: 2},
+			},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 3},
+				{PID: 3, Start: 0, Stop: 2, CPU: 1},
+				{PID: 2, Start: 3, Stop: 6},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := tt.scheduler.Schedule(tt.processes)
+			if !reflect.DeepEqual(got.Gantt, tt.wantGantt) {
+				t.Errorf("Gantt = %v, want %v", got.Gantt, tt.wantGantt)
+			}
+			if got.CPUs != 2 || got.Migrations != tt.wantMigrations {
+				t.Errorf("CPUs, Migrations = %d, %d, want 2, %d", got.CPUs, got.Migrations, tt.wantMigrations)
+			}
+		})
+	}
+}
+
+func TestMachine_heterogeneous(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 10},
+		{ProcessID: 2, BurstDuration: 10},
+		{ProcessID: 3, BurstDuration: 4, ArrivalTime: 2},
+	}
+	cores := []CoreStats{
+		{CPU: 0, Speed: 1, Busy: 14, Utilisation: 0.7, Energy: 112, Completed: 2},
+		{CPU: 1, Speed: 0.5, Busy: 20, Utilisation: 1, Energy: 20, Completed: 1},
+	}
+	tests := []struct {
+		name           string
+		scheduler      Scheduler
+		processes      []Process
+		wantGantt      []TimeSlice
+		wantCompletion []int64
+		wantWait       []int64
+		wantCores      []CoreStats
+	}{
+		{
+			name:      "a burst takes twice as long on a slow CPU",
+			scheduler: fcfs{machine{cpus: 2, little: 1, slowdown: 2}},
+			processes: processes,
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 10},
+				{PID: 2, Start: 0, Stop: 20, CPU: 1},
+				{PID: 3, Start: 10, Stop: 14},
+			},
+			wantCompletion: []int64{10, 20, 14},
+			wantWait:       []int64{0, 0, 8},
+			wantCores:      cores,
+		},
+		{
+			name:      "energy-aware placement fills slow CPUs first",
+			scheduler: fcfs{machine{cpus: 2, perCore: true, little: 1, slowdown: 2, energy: true}},
+			processes: processes,
+			wantGantt: []TimeSlice{
+				{PID: 2, Start: 0, Stop: 10},
+				{PID: 1, Start: 0, Stop: 20, CPU: 1},
+				{PID: 3, Start: 10, Stop: 14},
+			},
+			wantCompletion: []int64{20, 10, 14},
+			wantWait:       []int64{0, 0, 8},
+			wantCores:      cores,
+		},
+		{
+			name:      "partly run bursts move between speeds",
+			scheduler: rr{machine: machine{cpus: 2, little: 1, slowdown: 3}, quantum: 2},
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 3},
+				{ProcessID: 2, BurstDuration: 3},
+				{ProcessID: 3, BurstDuration: 3},
+			},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 2},
+				{PID: 2, Start: 0, Stop: 2, CPU: 1},
+				{PID: 3, Start: 2, Stop: 4},
+				{PID: 1, Start: 2, Stop: 4, CPU: 1},
+				{PID: 2, Start: 4, Stop: 6},
+				{PID: 3, Start: 4, Stop: 6, CPU: 1},
+				// The last third of a tick of burst still takes a tick.
+				{PID: 1, Start: 6, Stop: 7},
+				{PID: 2, Start: 6, Stop: 7, CPU: 1},
+				{PID: 3, Start: 7, Stop: 8},
+			},
+			wantCompletion: []int64{7, 7, 8},
+			wantWait:       []int64{2, 2, 3},
+			wantCores: []CoreStats{
+				{CPU: 0, Speed: 1, Busy: 8, Utilisation: 1, Energy: 216, Completed: 2},
+				{CPU: 1, Speed: 1.0 / 3, Busy: 7, Utilisation: 0.875, Energy: 7, Completed: 1},
+			},
+		},
+		{
+			name:           "a long run on the fastest CPU uses more energy than an int64 holds",
+			scheduler:      fcfs{machine{cpus: 2, little: 1, slowdown: maxSlowdown}},
+			processes:      []Process{{ProcessID: 1, BurstDuration: 1e10}},
+			wantGantt:      []TimeSlice{{PID: 1, Start: 0, Stop: 1e10}},
+			wantCompletion: []int64{1e10},
+			wantWait:       []int64{0},
+			wantCores: []CoreStats{
+				{CPU: 0, Speed: 1, Busy: 1e10, Utilisation: 1, Energy: 1e19, Completed: 1},
+				{CPU: 1, Speed: 0.001},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := tt.scheduler.Schedule(tt.processes)
+			if !reflect.DeepEqual(got.Gantt, tt.wantGantt) {
+				t.Errorf("Gantt = %v, want %v", got.Gantt, tt.wantGantt)
+			}
+			for i, row := range got.Processes {
+				if row.Completion != tt.wantCompletion[i] || row.Wait != tt.wantWait[i] {
+					t.Errorf("process %d completion, wait = %d, %d, want %d, %d",
+						row.ProcessID, row.Completion, row.Wait, tt.wantCompletion[i], tt.wantWait[i])
+				}
+			}
+			if !reflect.DeepEqual(got.Cores, tt.wantCores) {
+				t.Errorf("Cores = %+v, want %+v", got.Cores, tt.wantCores)
+			}
+		})
+	}
+}
+
+func TestMachine_io(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		scheduler Scheduler
+		processes []Process
+		wantGantt []TimeSlice
+		wantIO    []TimeSlice
+		// want is the wait, blocked time and completion of each process.
+		want [][3]int64
+	}{
+		{
+			name:      "processes queue for the device",
+			scheduler: fcfs{},
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 7, Bursts: []Burst{{Duration: 5}, {IO: true, Duration: 3}, {Duration: 2}}},
+				{ProcessID: 2, BurstDuration: 3, ArrivalTime: 1, Bursts: []Burst{{Duration: 2}, {IO: true, Duration: 4}, {Duration: 1}}},
+				{ProcessID: 3, BurstDuration: 4, ArrivalTime: 2},
+			},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 5},
+				{PID: 2, Start: 5, Stop: 7},
+				{PID: 3, Start: 7, Stop: 11},
+				{PID: 1, Start: 11, Stop: 13},
+				{PID: 2, Start: 13, Stop: 14},
+			},
+			wantIO: []TimeSlice{
+				{PID: 1, Start: 5, Stop: 8},
+				{PID: 2, Start: 8, Stop: 12},
+			},
+			want: [][3]int64{{3, 3, 13}, {5, 5, 14}, {5, 0, 11}},
+		},
+		{
+			name:      "starting and ending with I/O",
+			scheduler: sjf{},
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 2, Bursts: []Burst{{IO: true, Duration: 2}, {Duration: 2}, {IO: true, Duration: 3}}},
+				{ProcessID: 2, BurstDuration: 3, Bursts: []Burst{{Duration: 1}, {IO: true, Duration: 2}, {Duration: 2}}},
+			},
+			wantGantt: []TimeSlice{
+				{PID: 2, Start: 0, Stop: 1},
+				{PID: 1, Start: 2, Stop: 4},
+				{PID: 2, Start: 4, Stop: 6},
+			},
+			wantIO: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 2},
+				{PID: 2, Start: 2, Stop: 4},
+				{PID: 1, Start: 4, Stop: 7},
+			},
+			want: [][3]int64{{0, 5, 7}, {0, 3, 6}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := tt.scheduler.Schedule(tt.processes)
+			if !reflect.DeepEqual(got.Gantt, tt.wantGantt) {
+				t.Errorf("Gantt = %v, want %v", got.Gantt, tt.wantGantt)
+			}
+			if !reflect.DeepEqual(got.IO, tt.wantIO) {
+				t.Errorf("IO = %v, want %v", got.IO, tt.wantIO)
+			}
+			for i, row := range got.Processes {
+				if g := [3]int64{row.Wait, row.Blocked, row.Completion}; g != tt.want[i] {
+					t.Errorf("process %d wait, blocked, completion = %v, want %v", row.ProcessID, g, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMachine_longSlices(t *testing.T) {
+	t.Parallel()
+	// A slice longer than the time left runs the process to completion.
+	for _, quantum := range []int64{math.MaxInt64, math.MaxInt64 - 2} {
+		s, err := NewScheduler("rr", Parameters{"quantum": quantum})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, want := s.Schedule(exampleProcesses), fcfs{}.Schedule(exampleProcesses)
+		if !reflect.DeepEqual(got.Gantt, want.Gantt) {
+			t.Errorf("quantum %d: Gantt = %v, want %v", quantum, got.Gantt, want.Gantt)
+		}
+	}
+}
+
+func TestMachine_events(t *testing.T) {
+	t.Parallel()
+	// Processes arriving faster than they can be served keep the CPU busy
+	// throughout while the ready queue grows long.
+	const n = 100000
+	processes := make([]Process, n)
+	for i := range processes {
+		processes[i] = Process{ProcessID: int64(i + 1), ArrivalTime: int64(2 * i), BurstDuration: 3}
+	}
+	for _, name := range []string{"fcfs", "sjf", "rr", "priority", "cfs", "mlfq", "stride"} {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, err := NewScheduler(name, Parameters{"quantum": 3})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := s.Schedule(processes)
+			if _, last := span(got.Processes); last != 3*n {
+				t.Errorf("last completion = %d, want %d", last, 3*n)
+			}
+		})
+	}
+}
+
+func BenchmarkSimulate(b *testing.B) {
+	processes := make([]Process, 10000)
+	for i := range processes {
+		processes[i] = Process{ProcessID: int64(i + 1), ArrivalTime: int64(i * 3), BurstDuration: int64(1 + i*7%11), Priority: int64(1 + i%5)}
+	}
+	for _, name := range Algorithms() {
+		s, err := NewScheduler(name, nil)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				s.Schedule(processes)
+			}
+		})
+	}
+}
+
+// BenchmarkSimulate_million schedules a million processes with long bursts
+// arriving far faster than they can be served, so that hundreds of thousands
+// wait at once. The slices are long enough for a burst to take a few, and the
+// priority schedulers age.
+func BenchmarkSimulate_million(b *testing.B) {
+	processes := make([]Process, 1000000)
+	for i := range processes {
+		processes[i] = Process{ProcessID: int64(i + 1), ArrivalTime: int64(i * 3), BurstDuration: int64(1+i*7%11) * 100, Priority: int64(1 + i%50)}
+	}
+	params := Parameters{"quantum": 100, "latency": 1000, "granularity": 100, "aging": 1}
+	for _, name := range Algorithms() {
+		s, err := NewScheduler(name, params)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				s.Schedule(processes)
+			}
+		})
+	}
+}
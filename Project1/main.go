@@ -1,506 +1,668 @@
 package main
 
 import (
-    "encoding/csv"
-    "errors"
-    "fmt"
-    "io"
-    "log"
-    "os"
-    "strconv"
-    "strings"
-    "math"
-    "github.com/olekukonko/tablewriter"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
 )
 
+// defaultAlgorithms are run, in order, when no algorithm is chosen.
+var defaultAlgorithms = []string{"fcfs", "sjf", "priority", "rr"}
+
 func main() {
-    // CLI args
-    f, closeFile, err := openProcessingFile(os.Args...)
-    if err != nil {
-        log.Fatal(err)
-    }
-    defer closeFile()
-
-    // Load and parse processes
-    processes, err := loadProcesses(f)
-    if err != nil {
-        log.Fatal(err)
-    }
-
-    // First-come, first-serve scheduling
-    FCFSSchedule(os.Stdout, "First-come, first-serve", processes)
-    // Shortest Job First (SJF)
-    SJFSchedule(os.Stdout, "Shortest-job-first", processes)
-   // SJF Priority
-    SJFPrioritySchedule(os.Stdout, "Priority", processes)
-   // Round-robin (RR)
-    RRSchedule(os.Stdout, "Round-robin", processes)
+	os.Exit(run(os.Args, os.Stdin, os.Stdout, os.Stderr))
 }
 
-func openProcessingFile(args ...string) (*os.File, func(), error) {
-    if len(args) != 2 {
-        return nil, nil, fmt.Errorf("%w: must give a scheduling file to process", ErrInvalidArgs)
-    }
-    // Read in CSV process CSV file
-    f, err := os.Open(args[1])
-    if err != nil {
-        return nil, nil, fmt.Errorf("%v: error opening scheduling file", err)
-    }
-    closeFn := func() {
-        if err := f.Close(); err != nil {
-            log.Fatalf("%v: error closing scheduling file", err)
-        }
-    }
-    return f, closeFn, nil
+// openWorkload opens the workload CSV at path.
+func openWorkload(path string) (*os.File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%v: error opening scheduling file", err)
+	}
+	return f, nil
 }
 
 type (
-    Process struct {
-        ProcessID     int64
-        ArrivalTime   int64
-        BurstDuration int64
-        Priority      int64
-    }
-    RunTime struct {
-        ProcessID int64
-        waitTime  int64
-        remainTime int64
-    }
-       TimeSlice struct {
-        PID   int64
-        Start int64
-        Stop  int64
-    }
+	Process struct {
+		ProcessID     int64 `json:"id"`
+		ArrivalTime   int64 `json:"arrival"`
+		BurstDuration int64 `json:"burst"`
+		Priority      int64 `json:"priority"`
+		// Class is the optional process class used by multilevel queue
+		// scheduling: system, interactive or batch.
+		Class string `json:"class,omitempty"`
+		// Tickets is the optional lottery and stride scheduling share,
+		// otherwise derived from Priority.
+		Tickets int64 `json:"tickets,omitempty"`
+		// Deadline is the optional time by which the process should
+		// complete, used by earliest deadline first scheduling. For a
+		// periodic task it is relative to each release.
+		Deadline int64 `json:"deadline,omitempty"`
+		// Period makes the process a periodic task, released every Period
+		// ticks from its arrival time with a burst of its worst-case
+		// execution time.
+		Period int64 `json:"period,omitempty"`
+		// Job is the instance number, from 1, of a job released by a
+		// periodic task.
+		Job int64 `json:"job,omitempty"`
+		// Affinity is a bit mask of the CPUs the process may run on, CPU 0
+		// being the lowest bit; 0 allows any.
+		Affinity uint64 `json:"affinity,omitempty"`
+		// Bursts is the sequence of CPU and I/O bursts of a process that
+		// does I/O, BurstDuration then being the total of its CPU bursts.
+		Bursts []Burst `json:"bursts,omitempty"`
+	}
+	// Burst is a spell of running on a CPU, or of using the I/O device.
+	Burst struct {
+		IO       bool  `json:"io,omitempty"`
+		Duration int64 `json:"duration"`
+	}
+	TimeSlice struct {
+		PID   int64 `json:"pid"`
+		Start int64 `json:"start"`
+		Stop  int64 `json:"stop"`
+		// Job is the job of a periodic task the slice belongs to.
+		Job int64 `json:"job,omitempty"`
+		// Switch marks time spent switching the CPU to the process.
+		Switch bool `json:"switch,omitempty"`
+		// CPU is the processor the slice ran on.
+		CPU int `json:"cpu,omitempty"`
+	}
 )
-//region Schedulers
-// FCFSSchedule outputs a schedule of processes in a GANTT chart and a table of timing given:
-// • an output writer
-// • a title for the chart
-// • a slice of processes
-func FCFSSchedule(w io.Writer, title string, processes []Process) {
-    var (
-        serviceTime     int64
-        totalWait       float64
-        turnAroundTotal float64
-        lasttimeComplete  float64
-        tWait     int64
-        schedule        = make([][]string, len(processes))
-        gantt           = make([]TimeSlice, 0)
-    )
-    for i := range processes {
-        if processes[i].ArrivalTime > 0 {
-            tWait = serviceTime - processes[i].ArrivalTime
-        }
-        totalWait += float64(tWait)
-
-        start := tWait + processes[i].ArrivalTime
-
-        turnaround := processes[i].BurstDuration + tWait
-        turnAroundTotal += float64(turnaround)
-
-        timeComplete := processes[i].BurstDuration + processes[i].ArrivalTime + tWait
-        lasttimeComplete = float64(timeComplete)
-
-		schedule[i] = []string{
-            fmt.Sprint(processes[i].ProcessID),
-            fmt.Sprint(processes[i].Priority),
-            fmt.Sprint(processes[i].BurstDuration),
-            fmt.Sprint(processes[i].ArrivalTime),
-            fmt.Sprint(tWait),
-            fmt.Sprint(turnaround),
-            fmt.Sprint(timeComplete),
-        }
-        serviceTime += processes[i].BurstDuration
-
-		gantt = append(gantt, TimeSlice{
-            PID:   processes[i].ProcessID,
-            Start: start,
-            Stop:  serviceTime,
-        })
-    }
-
-    count := float64(len(processes))
-    aveWait := totalWait / count
-    aveTurnaround := turnAroundTotal / count
-    aveThroughput := count / lasttimeComplete
 
+// label names a process in charts and tables: its ID, followed by the job
+// number for a job of a periodic task.
+func (p Process) label() string { return jobLabel(p.ProcessID, p.Job) }
+
+func (s TimeSlice) label() string {
+	if s.Switch {
+		return "cs"
+	}
+	return jobLabel(s.PID, s.Job)
+}
+
+func jobLabel(pid, job int64) string {
+	if job == 0 {
+		return strconv.FormatInt(pid, 10)
+	}
+	return fmt.Sprintf("%d.%d", pid, job)
+}
+
+//region Output helpers
+
+// outputResult writes the title, Gantt chart and schedule table of a result.
+func outputResult(w io.Writer, title string, res Result) {
 	outputTitle(w, title)
-    outputGantt(w, gantt)
-    outputSchedule(w, schedule, aveWait, aveTurnaround, aveThroughput)
+	outputGantt(w, res)
+	if res.CPUs > 1 {
+		_, _ = fmt.Fprintf(w, "Migrations: %d\n\n", res.Migrations)
+	}
+	outputSchedule(w, res.Processes, res.Stats)
+	if len(res.IO) > 0 {
+		outputIO(w, res.Processes, res.IO)
+	}
+	if len(res.Cores) > 0 {
+		outputCores(w, res.Cores)
+	}
+	if res.Switches != nil {
+		outputSwitches(w, *res.Switches)
+	}
+	if res.Deadlines != nil {
+		outputDeadlines(w, res.Processes, *res.Deadlines)
+	}
+	if len(res.Shares) > 0 {
+		outputShares(w, res.Shares)
+	}
+	if res.Analysis != nil {
+		outputAnalysis(w, *res.Analysis)
+	}
+	if len(res.Priorities) > 0 {
+		outputPriorities(w, res.Priorities)
+	}
+	if len(res.Windows) > 0 {
+		outputWindows(w, res.Windows)
+	}
 }
 
-//Implement SJF priority scheduling (preemptive) and report average turnaround time, average waiting time, and average throughput.
-func SJFPrioritySchedule(w io.Writer, title string, processes []Process) {
-    var (
-        totalWait       float64
-        turnAroundTotal float64
-        lasttimeComplete  float64
-        tWait     int64
-        totalTime       int64
-        activeProc      int64
-        highestAvail    int64
-        processStart    int64
-        trackSJF = make([]RunTime, len(processes))
-        schedule = make([][]string, len(processes))
-        gantt = make([]TimeSlice, 0)
-    )
-      
-    //This calculate total needed time and poluate the time tracker
-    for i := range processes {
-        trackSJF[i].ProcessID = processes[i].ProcessID
-        trackSJF[i].waitTime = 0
-        trackSJF[i].remainTime = processes[i].BurstDuration
-        totalTime += processes[i].BurstDuration
-    }
-
-    activeProc = 0
-    processStart = 0
-    highestAvail = getShortest(trackSJF, processes, 0)
-    activeProc = highestAvail
-   
-	for t := 0; t <= int(totalTime); t++ {
-        for i := range processes {
-            //Checks if higher priority process arrives
-            if (processes[i].Priority < processes[activeProc].Priority) && (t == int(processes[i].ArrivalTime)) {
-                highestAvail = int64(i)
-            }
-            //Checks an equal priorty process arrives and it is shorter
-            if (processes[i].Priority == processes[activeProc].Priority) && (t == int(processes[i].ArrivalTime)) && (trackSJF[i].remainTime < trackSJF[activeProc].remainTime) {
-                highestAvail = int64(i)
-            }
-            //Will increment wait time if process has arrived & not executing
-            if (i != int(activeProc) && i != int(highestAvail)) && (trackSJF[i].remainTime > 0) && (t > int(processes[i].ArrivalTime)) {
-                trackSJF[i].waitTime += 1
-            }
-            //Check if running process has completed
-            if (i == int(activeProc)) && (trackSJF[i].remainTime == 0) {
-                highestAvail = getHighest(trackSJF, processes, int64(t))
-            }
-            //Decrement the running process remainTime
-            if i == int(activeProc) {
-                trackSJF[i].remainTime -= 1
-            }
-        }
-        //Update Gannt chart
-        if activeProc != highestAvail || t == int(totalTime) {
-            gantt = append(gantt, TimeSlice{
-                PID:   processes[activeProc].ProcessID,
-                Start: processStart,
-                Stop:  int64(t),
-            })
-            processStart = int64(t)
-            activeProc = highestAvail
-        }
-    }
-    //Record final results
-    for i := range processes {
-		tWait = trackSJF[i].waitTime
-        totalWait += float64(tWait)
-        turnaround := processes[i].BurstDuration + trackSJF[i].waitTime
-        turnAroundTotal += float64(turnaround)
-        timeComplete := processes[i].BurstDuration + processes[i].ArrivalTime + tWait
-        lasttimeComplete = float64(timeComplete)
-        schedule[i] = []string{
-            fmt.Sprint(processes[i].ProcessID),
-            fmt.Sprint(processes[i].Priority),
-            fmt.Sprint(processes[i].BurstDuration),
-            fmt.Sprint(processes[i].ArrivalTime),
-            fmt.Sprint(tWait),
-            fmt.Sprint(turnaround),
-            fmt.Sprint(timeComplete),
-        }
-    }
-    //Calculate average and output results
-    count := float64(len(processes))
-    aveWait := totalWait / count
-    aveTurnaround := turnAroundTotal / count
-    aveThroughput := count / lasttimeComplete
-    outputTitle(w, title)
-    outputGantt(w, gantt)
-    outputSchedule(w, schedule, aveWait, aveTurnaround, aveThroughput)
+func outputTitle(w io.Writer, title string) {
+	_, _ = fmt.Fprintln(w, strings.Repeat("-", len(title)*2))
+	_, _ = fmt.Fprintln(w, strings.Repeat(" ", len(title)/2), title)
+	_, _ = fmt.Fprintln(w, strings.Repeat("-", len(title)*2))
 }
-//Implement SJF (preemptive) and report average turnaround time, average waiting time, and average throughput.
-func SJFSchedule(w io.Writer, title string, processes []Process) {
-    var (
-        serviceTime     int64
-        lastStart       int64
-		timeLess         int64
-        totalWait       float64
-        turnAroundTotal float64
-        lasttimeComplete  float64  
-        schedule        = make([][]string, len(processes))
-        gantt           = make([]TimeSlice, 0)
-        tWait           = make([]int64, len(processes))
-        turnAroundTime  = make([]int64, len(processes))
-        tRemain         = make([]int64, len(processes))
-        timeComplete      = make([]int64, len(processes))
-    )
-    completed := 0
-    shortest := 0
-    lastShortest := 0
-    timeLess = math.MaxInt64
-    check := false
-    count := len(processes)
-   
-    for i := range processes {
-                tRemain[i] = processes[i].BurstDuration
-    }
-    for completed != count {
-        for j := 0; j < count; j++ {
-            if processes[j].ArrivalTime <= serviceTime && tRemain[j] < timeLess && tRemain[j] > 0 {
-                timeLess = tRemain[j]
-                shortest = j
-                check = true
-            }
-        }
-        // Update Gantt schedule
-        if shortest != lastShortest {
-            gantt = append(gantt, TimeSlice{
-                PID:   processes[lastShortest].ProcessID,
-                Start: lastStart,
-                Stop:  serviceTime,
-            })
-            lastStart = serviceTime
-            lastShortest = shortest
-        }
-        if check == false {
-            serviceTime++
-            continue
-        }
-        tRemain[shortest]--
-        timeLess = tRemain[shortest]
-        if (timeLess == 0) {
-            timeLess = math.MaxInt64
-        }
-        if tRemain[shortest] == 0 {
-            completed++
-            check = false
-            timeComplete[shortest] = serviceTime + 1
-            lasttimeComplete = float64(timeComplete[shortest])
-            tWait[shortest] = timeComplete[shortest] - processes[shortest].BurstDuration - processes[shortest].ArrivalTime
-            if tWait[shortest] < 0 {
-                tWait[shortest] = 0
-            }
-        }
-        serviceTime++
-    }
-    //Calculate total wait and turn around time
-    for i := range tWait {
-        totalWait += float64(tWait[i])
-        turnAroundTime[i] = processes[i].BurstDuration + tWait[i]
-        turnAroundTotal += float64(turnAroundTime[i])
-    }
-    //Calculate average
-    aveWait := totalWait / float64(count)
-    aveTurnaround := turnAroundTotal / float64(count)
-    aveThroughput := float64(count) / lasttimeComplete
-   
-    for i := range processes {
-        schedule[i] = []string{
-            fmt.Sprint(processes[i].ProcessID),
-            fmt.Sprint(processes[i].Priority),
-            fmt.Sprint(processes[i].BurstDuration),
-            fmt.Sprint(processes[i].ArrivalTime),
-            fmt.Sprint(tWait[i]),
-            fmt.Sprint(turnAroundTime[i]),
-            fmt.Sprint(timeComplete[i]),
-        }
-    }
-    //Add last entry and generate results
-    gantt = append(gantt, TimeSlice{
-        PID:   processes[lastShortest].ProcessID,
-        Start: lastStart,
-        Stop:  serviceTime,
-    })
-    outputTitle(w, title)
-    outputGantt(w, gantt)
-    outputSchedule(w, schedule, aveWait, aveTurnaround, aveThroughput)
+
+// outputGantt writes the Gantt chart, with a lane per CPU when there are
+// several and one for the I/O device when processes do I/O.
+func outputGantt(w io.Writer, res Result) {
+	_, _ = fmt.Fprintln(w, "Gantt schedule")
+	lanes := ganttLanes(res.Gantt, res.CPUs)
+	if lanes == 1 && len(res.IO) == 0 {
+		outputGanttLane(w, res.Gantt)
+		_, _ = fmt.Fprintln(w)
+		return
+	}
+	byCPU := make([][]TimeSlice, lanes)
+	for _, slice := range res.Gantt {
+		byCPU[slice.CPU] = append(byCPU[slice.CPU], slice)
+	}
+	for cpu, lane := range byCPU {
+		_, _ = fmt.Fprintf(w, "CPU %d\n", cpu)
+		outputGanttLane(w, lane)
+	}
+	if len(res.IO) > 0 {
+		_, _ = fmt.Fprintln(w, "I/O")
+		outputGanttLane(w, res.IO)
+	}
+	_, _ = fmt.Fprintln(w)
 }
 
-//Round-round (preemptive) and report average turnaround time, average waiting time, and average throughput.
-func RRSchedule(w io.Writer, title string, processes []Process) {
-    var (
-        serviceTime     int64
-        lastStart       int64
-        timeQuantum     int64
-        totalWait       float64
-        turnAroundTotal float64
-        lasttimeComplete  float64  
-        schedule = make([][]string, len(processes))
-        gantt = make([]TimeSlice, 0)
-        turnAroundTime = make([]int64, len(processes))
-        tWait = make([]int64, len(processes))
-        tRemain = make([]int64, len(processes))
-        timeComplete = make([]int64, len(processes))
-    )
-    timeQuantum = 1
-    stuck := 0
-    completed := 0
-    turn := 0
-    count := len(processes)
-    check := false
-   
-    for i := range processes {
-                tRemain[i] = processes[i].BurstDuration
-    }
-
-    for completed != count {
-        if processes[turn].ArrivalTime > serviceTime || tRemain[turn] == 0 {
-            turn = (turn + 1) % count
-            if check == false {
-                check = true
-                stuck = turn
-            } else if stuck == turn {
-                serviceTime++
-                lastStart = serviceTime
-                check = false
-                turn = 0
-            }
-            continue
-        }
-        check = false
-        if tRemain[turn] > timeQuantum {
-            serviceTime += timeQuantum
-            tRemain[turn] -= timeQuantum
-        } else {
-            serviceTime += tRemain[turn]
-            tRemain[turn] = 0
-            completed++
-            timeComplete[turn] = serviceTime
-            lasttimeComplete = float64(timeComplete[turn])
-            tWait[turn] = timeComplete[turn] - processes[turn].BurstDuration - processes[turn].ArrivalTime
-            if tWait[turn] < 0 {
-                tWait[turn] = 0
-            }  
-        }
-        gantt = append(gantt, TimeSlice{
-            PID:   processes[turn].ProcessID,
-            Start: lastStart,
-            Stop:  serviceTime,
-        })
-        lastStart = serviceTime
-        turn = (turn + 1) % count
-    }
-    //Calculate total wait and turn around time
-    for i := range tWait {
-        totalWait += float64(tWait[i])
-        turnAroundTime[i] = processes[i].BurstDuration + tWait[i]
-        turnAroundTotal += float64(turnAroundTime[i])
-    }
-    aveWait := totalWait / float64(count)
-    aveTurnaround := turnAroundTotal / float64(count)
-    aveThroughput := float64(count) / lasttimeComplete
-
-    for i := range processes {
-        schedule[i] = []string{
-            fmt.Sprint(processes[i].ProcessID),
-            fmt.Sprint(processes[i].Priority),
-            fmt.Sprint(processes[i].BurstDuration),
-            fmt.Sprint(processes[i].ArrivalTime),
-            fmt.Sprint(tWait[i]),
-            fmt.Sprint(turnAroundTime[i]),
-            fmt.Sprint(timeComplete[i]),
-        }
-    }
-    //Generate results
-    outputTitle(w, title)
-    outputGantt(w, gantt)
-    outputSchedule(w, schedule, aveWait, aveTurnaround, aveThroughput)  
+func outputGanttLane(w io.Writer, gantt []TimeSlice) {
+	_, _ = fmt.Fprint(w, "|")
+	for i := range gantt {
+		pid := gantt[i].label()
+		padding := strings.Repeat(" ", (8-len(pid))/2)
+		_, _ = fmt.Fprint(w, padding, pid, padding, "|")
+	}
+	_, _ = fmt.Fprintln(w)
+	for i := range gantt {
+		_, _ = fmt.Fprint(w, fmt.Sprint(gantt[i].Start), "\t")
+		if len(gantt)-1 == i {
+			_, _ = fmt.Fprint(w, fmt.Sprint(gantt[i].Stop))
+		}
+	}
+	_, _ = fmt.Fprintln(w)
 }
 
-// Returns the index of the shortest job that has an arrival time at or before the specified current time
-func getShortest(tracker []RunTime, processes []Process, current int64) (shortest int64) {
-    shortest = 0
-
-    for i := range processes {
-        if tracker[shortest].remainTime <= 0 {
-            shortest += 1
-            continue
-        }
-        if (tracker[i].remainTime < tracker[shortest].remainTime) && (processes[i].ArrivalTime <= current) && (tracker[i].remainTime > 0) {
-            shortest = int64(i)
-        }
-    }
-    return
+func outputSchedule(w io.Writer, rows []ProcessResult, stats Stats) {
+	_, _ = fmt.Fprintln(w, "Schedule table")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"ID", "Priority", "Burst", "Arrival", "Wait", "Response", "Turnaround", "Exit"})
+	for _, row := range rows {
+		table.Append([]string{
+			row.label(),
+			fmt.Sprint(row.Priority),
+			fmt.Sprint(row.BurstDuration),
+			fmt.Sprint(row.ArrivalTime),
+			fmt.Sprint(row.Wait),
+			fmt.Sprint(row.Response),
+			fmt.Sprint(row.Turnaround),
+			fmt.Sprint(row.Completion),
+		})
+	}
+	table.SetFooter([]string{"", "", "", "",
+		fmt.Sprintf("Average\n%.2f", stats.AverageWait),
+		fmt.Sprintf("Average\n%.2f", stats.AverageResponse),
+		fmt.Sprintf("Average\n%.2f", stats.AverageTurnaround),
+		fmt.Sprintf("Throughput\n%.2f/t", stats.Throughput)})
+	table.Render()
+	_, _ = fmt.Fprintf(w, "Max wait: %d\n", stats.MaxWait)
+	_, _ = fmt.Fprintf(w, "P95 wait: %.2f\n", stats.P95Wait)
+	_, _ = fmt.Fprintf(w, "Normalised turnaround: %.2f\n", stats.AverageNormalisedTurnaround)
+	_, _ = fmt.Fprintf(w, "Fairness: %.2f\n", stats.Fairness)
+	_, _ = fmt.Fprintf(w, "Utilisation: %.1f%%\n", 100*stats.Utilisation)
+	_, _ = fmt.Fprintf(w, "Idle: %d\n", stats.Idle)
+	_, _ = fmt.Fprintf(w, "Context switches: %d\n", stats.ContextSwitches)
 }
-// Returns index of the highest priortity and shortest job avaiable at the current clock time
-func getHighest(tracker []RunTime, processes []Process, current int64) (highest int64) {
-    highest = 0
-
-    for i := range processes {
-        if tracker[highest].remainTime <= 0 {
-            highest += 1
-            continue
-        }
-        if (processes[i].Priority < processes[highest].Priority) && (processes[i].ArrivalTime <= current) && (tracker[i].remainTime > 0) {
-            highest = int64(i)
-        }
-    }
-    return
+
+func outputIO(w io.Writer, rows []ProcessResult, io []TimeSlice) {
+	_, _ = fmt.Fprintln(w, "I/O")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"ID", "Bursts", "Blocked"})
+	table.SetAutoWrapText(false)
+	for _, row := range rows {
+		bursts := []string{fmt.Sprintf("cpu:%d", row.BurstDuration)}
+		if len(row.Bursts) > 0 {
+			bursts = make([]string, len(row.Bursts))
+		}
+		for i, b := range row.Bursts {
+			kind := "cpu"
+			if b.IO {
+				kind = "io"
+			}
+			bursts[i] = fmt.Sprintf("%s:%d", kind, b.Duration)
+		}
+		table.Append([]string{
+			row.label(),
+			strings.Join(bursts, ","),
+			fmt.Sprint(row.Blocked),
+		})
+	}
+	var busy int64
+	for _, slice := range io {
+		busy += slice.Stop - slice.Start
+	}
+	utilisation := 0.0
+	if first, last := span(rows); last > first {
+		utilisation = float64(busy) / float64(last-first)
+	}
+	table.SetFooter([]string{"", fmt.Sprintf("Device busy\n%.1f%%", 100*utilisation), ""})
+	table.Render()
 }
-//region Output helpers
-func outputTitle(w io.Writer, title string) {
-    _, _ = fmt.Fprintln(w, strings.Repeat("-", len(title)*2))
-    _, _ = fmt.Fprintln(w, strings.Repeat(" ", len(title)/2), title)
-    _, _ = fmt.Fprintln(w, strings.Repeat("-", len(title)*2))
+
+func outputCores(w io.Writer, cores []CoreStats) {
+	_, _ = fmt.Fprintln(w, "CPUs")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"CPU", "Speed", "Busy", "Utilisation", "Completed", "Energy"})
+	var energy float64
+	for _, c := range cores {
+		table.Append([]string{
+			fmt.Sprint(c.CPU),
+			// Three significant figures show the slowest CPUs too.
+			fmt.Sprintf("%.3g", c.Speed),
+			fmt.Sprint(c.Busy),
+			fmt.Sprintf("%.1f%%", 100*c.Utilisation),
+			fmt.Sprint(c.Completed),
+			fmt.Sprint(c.Energy),
+		})
+		energy += c.Energy
+	}
+	table.SetFooter([]string{"", "", "", "", "", fmt.Sprintf("Total\n%v", energy)})
+	table.Render()
+}
+
+func outputWindows(w io.Writer, windows []ThroughputWindow) {
+	_, _ = fmt.Fprintln(w, "Throughput")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Window", "Completed", "Throughput"})
+	for _, window := range windows {
+		table.Append([]string{
+			fmt.Sprintf("%d-%d", window.Start, window.Stop),
+			fmt.Sprint(window.Completed),
+			fmt.Sprintf("%.2f/t", window.Throughput),
+		})
+	}
+	table.Render()
 }
 
-func outputGantt(w io.Writer, gantt []TimeSlice) {
-    _, _ = fmt.Fprintln(w, "Gantt schedule")
-    _, _ = fmt.Fprint(w, "|")
-    for i := range gantt {
-        pid := fmt.Sprint(gantt[i].PID)
-        padding := strings.Repeat(" ", (8-len(pid))/2)
-        _, _ = fmt.Fprint(w, padding, pid, padding, "|")
-    }
-    _, _ = fmt.Fprintln(w)
-    for i := range gantt {
-        _, _ = fmt.Fprint(w, fmt.Sprint(gantt[i].Start), "\t")
-        if len(gantt)-1 == i {
-            _, _ = fmt.Fprint(w, fmt.Sprint(gantt[i].Stop))
-        }
-    }
-    _, _ = fmt.Fprintf(w, "\n\n")
+func outputSwitches(w io.Writer, stats SwitchStats) {
+	_, _ = fmt.Fprintln(w, "Context switches")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Switches", "Time lost", "CPU utilisation"})
+	table.Append([]string{
+		fmt.Sprint(stats.Count),
+		fmt.Sprint(stats.Time),
+		fmt.Sprintf("%.1f%%", 100*stats.Utilisation),
+	})
+	table.Render()
 }
 
-func outputSchedule(w io.Writer, rows [][]string, wait, turnaround, throughput float64) {
-    _, _ = fmt.Fprintln(w, "Schedule table")
-    table := tablewriter.NewWriter(w)
-    table.SetHeader([]string{"ID", "Priority", "Burst", "Arrival", "Wait", "Turnaround", "Exit"})
-    table.AppendBulk(rows)
-    table.SetFooter([]string{"", "", "", "",
-        fmt.Sprintf("Average\n%.2f", wait),
-        fmt.Sprintf("Average\n%.2f", turnaround),
-        fmt.Sprintf("Throughput\n%.2f/t", throughput)})
-    table.Render()
+func outputDeadlines(w io.Writer, rows []ProcessResult, stats DeadlineStats) {
+	_, _ = fmt.Fprintln(w, "Deadlines")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"ID", "Deadline", "Exit", "Lateness", "Missed"})
+	for _, row := range rows {
+		if row.Deadline <= 0 {
+			continue
+		}
+		missed := "no"
+		if row.Missed {
+			missed = "yes"
+		}
+		table.Append([]string{
+			row.label(),
+			fmt.Sprint(row.Deadline),
+			fmt.Sprint(row.Completion),
+			fmt.Sprint(row.Lateness),
+			missed,
+		})
+	}
+	table.SetFooter([]string{"", "", "",
+		fmt.Sprintf("Total\n%d", stats.TotalLateness),
+		fmt.Sprintf("Miss ratio\n%.2f", stats.MissRatio)})
+	table.Render()
 }
+
+func outputShares(w io.Writer, shares []Share) {
+	_, _ = fmt.Fprintln(w, "CPU share")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"ID", "Tickets", "Expected", "Received", "Received/Expected"})
+	for _, s := range shares {
+		ratio := "-"
+		if s.Expected > 0 {
+			ratio = fmt.Sprintf("%.2f", s.Received/s.Expected)
+		}
+		table.Append([]string{
+			fmt.Sprint(s.PID),
+			fmt.Sprint(s.Tickets),
+			fmt.Sprintf("%.1f%%", 100*s.Expected),
+			fmt.Sprintf("%.1f%%", 100*s.Received),
+			ratio,
+		})
+	}
+	table.Render()
+}
+
+func outputAnalysis(w io.Writer, a Analysis) {
+	_, _ = fmt.Fprintln(w, "Schedulability")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"ID", "Period", "WCET", "Deadline", "Utilisation", "Response", "Schedulable"})
+	for _, t := range a.Tasks {
+		schedulable := "no"
+		if t.Schedulable {
+			schedulable = "yes"
+		}
+		table.Append([]string{
+			fmt.Sprint(t.PID),
+			fmt.Sprint(t.Period),
+			fmt.Sprint(t.WCET),
+			fmt.Sprint(t.Deadline),
+			fmt.Sprintf("%.3f", t.Utilisation),
+			fmt.Sprint(t.Response),
+			schedulable,
+		})
+	}
+	schedulable := "no"
+	if a.Schedulable {
+		schedulable = "yes"
+	}
+	table.SetFooter([]string{"", "", "",
+		fmt.Sprintf("L&L bound\n%.3f", a.Bound),
+		fmt.Sprintf("Total\n%.3f", a.Utilisation),
+		"", schedulable})
+	table.Render()
+}
+
+func outputPriorities(w io.Writer, history []PriorityHistory) {
+	_, _ = fmt.Fprintln(w, "Effective priority")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"ID", "Base", "Final", "Over time (tick:priority)"})
+	table.SetAutoWrapText(false)
+	for _, h := range history {
+		changes := make([]string, len(h.Changes))
+		for i, c := range h.Changes {
+			changes[i] = fmt.Sprintf("%d:%d", c.At, c.Priority)
+		}
+		table.Append([]string{
+			jobLabel(h.PID, h.Job),
+			fmt.Sprint(h.Changes[0].Priority),
+			fmt.Sprint(h.Changes[len(h.Changes)-1].Priority),
+			strings.Join(changes, " "),
+		})
+	}
+	table.Render()
+}
+
 //endregion
+
 //region Loading processes.
-var ErrInvalidArgs = errors.New("invalid args")
 
+var (
+	ErrInvalidArgs  = errors.New("invalid args")
+	ErrUnknownClass = errors.New("unknown process class")
+	// ErrInvalidAffinity is returned for a CPU list that is not of the form
+	// 0;2 with CPUs below 64.
+	ErrInvalidAffinity = errors.New("invalid CPU affinity")
+	// ErrInvalidBursts is returned for a burst sequence that is not of the
+	// form cpu:5,io:3,cpu:2 with positive durations and at least one CPU
+	// burst.
+	ErrInvalidBursts = errors.New("invalid burst sequence")
+
+	ErrEmptyWorkload = errors.New("empty workload")
+	ErrMissingField  = errors.New("missing field")
+	ErrExtraField    = errors.New("unexpected field")
+	ErrNotInteger    = errors.New("not an integer")
+	ErrNegative      = errors.New("must not be negative")
+	ErrDuplicateID   = errors.New("duplicate process ID")
+	ErrPriorityRange = errors.New("priority outside [1-50]")
+	ErrBurstRange    = errors.New("burst too long")
+	ErrTicketRange   = errors.New("too many tickets")
+)
+
+// maxBurst is the longest CPU burst of a process, which the slowest CPU
+// takes maxSlowdown ticks a tick of to run without overflowing.
+const maxBurst = math.MaxInt64 / maxSlowdown
+
+// maxTickets is the most tickets a process may hold, so that the tickets of
+// any workload that fits in memory add up without overflowing.
+const maxTickets = math.MaxInt32
+
+// Priorities range from minPriority, the highest, to maxPriority.
+const (
+	minPriority = 1
+	maxPriority = 50
+)
+
+// workloadColumns names the columns of a workload CSV, in order.
+var workloadColumns = []string{"ID", "burst", "arrival", "priority", "class", "tickets", "deadline", "period", "affinity"}
+
+type (
+	// FieldError is a problem with a field of a workload CSV, or with a
+	// whole line when Column is 0.
+	FieldError struct {
+		Line, Column int
+		Err          error
+	}
+
+	// LoadErrors are all the problems found loading a workload.
+	LoadErrors []*FieldError
+)
+
+func (e *FieldError) Error() string {
+	switch {
+	case e.Column == 0:
+		return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+	case e.Column > len(workloadColumns):
+		return fmt.Sprintf("line %d, column %d: %v", e.Line, e.Column, e.Err)
+	}
+	return fmt.Sprintf("line %d, column %d (%s): %v", e.Line, e.Column, workloadColumns[e.Column-1], e.Err)
+}
+
+func (e *FieldError) Unwrap() error { return e.Err }
+
+func (e LoadErrors) Error() string {
+	problems := make([]string, len(e))
+	for i, err := range e {
+		problems[i] = err.Error()
+	}
+	return strings.Join(problems, "\n")
+}
+
+// Is reports whether any of the problems is target.
+func (e LoadErrors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadProcesses reads a workload CSV, returning LoadErrors listing every
+// problem with it. Lines starting with # are comments, and a first line
+// without any integer fields is a header.
 func loadProcesses(r io.Reader) ([]Process, error) {
-    rows, err := csv.NewReader(r).ReadAll()
-    if err != nil {
-        return nil, fmt.Errorf("%w: reading CSV", err)
-    }
-	processes := make([]Process, len(rows))
-    for i := range rows {
-        processes[i].ProcessID = mustStrToInt(rows[i][0])
-        processes[i].BurstDuration = mustStrToInt(rows[i][1])
-        processes[i].ArrivalTime = mustStrToInt(rows[i][2])
-        if len(rows[i]) == 4 {
-            processes[i].Priority = mustStrToInt(rows[i][3])
-        }
-    }
-    return processes, nil
+	cr := csv.NewReader(r)
+	cr.Comment = '#'
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	var (
+		processes []Process
+		problems  LoadErrors
+		lines     = make(map[int64]int)
+	)
+	for first := true; ; first = false {
+		row, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			// The reader cannot be trusted to find the next record.
+			problems = append(problems, &FieldError{Line: parseErr.Line, Err: parseErr.Err})
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: reading CSV", err)
+		}
+		if first && isHeader(row) {
+			continue
+		}
+		line, _ := cr.FieldPos(0)
+		p, errs := parseProcess(row, line)
+		// A process without a valid ID cannot be a duplicate.
+		if len(errs) == 0 || errs[0].Column != 1 {
+			if seen, ok := lines[p.ProcessID]; ok {
+				problems = append(problems, &FieldError{Line: line, Column: 1, Err: fmt.Errorf("%w %d, first on line %d", ErrDuplicateID, p.ProcessID, seen)})
+			} else {
+				lines[p.ProcessID] = line
+			}
+		}
+		problems = append(problems, errs...)
+		processes = append(processes, p)
+	}
+	if len(problems) > 0 {
+		return nil, problems
+	}
+	if len(processes) == 0 {
+		return nil, ErrEmptyWorkload
+	}
+	return processes, nil
 }
 
-func mustStrToInt(s string) int64 {
-    i, err := strconv.ParseInt(s, 10, 64)
-    if err != nil {
-        _, _ = fmt.Fprintln(os.Stderr, err)
-        os.Exit(1)
-    }
-    return i
+// isHeader reports whether row is a header, with no integer fields.
+func isHeader(row []string) bool {
+	for _, field := range row {
+		if _, err := strconv.ParseInt(field, 10, 64); err == nil {
+			return false
+		}
+	}
+	return true
 }
+
+// parseProcess parses a row of a workload CSV found on line, returning the
+// problems with each of its fields.
+func parseProcess(row []string, line int) (Process, LoadErrors) {
+	var (
+		p        Process
+		problems LoadErrors
+	)
+	fail := func(column int, err error) {
+		problems = append(problems, &FieldError{Line: line, Column: column, Err: err})
+	}
+	// field returns the column, from 1, and whether it was given.
+	field := func(column int) (string, bool) {
+		if column > len(row) || row[column-1] == "" {
+			return "", false
+		}
+		return row[column-1], true
+	}
+	// integer parses the column, which must be given when required.
+	integer := func(column int, required bool) (int64, bool) {
+		s, ok := field(column)
+		if !ok {
+			if required {
+				fail(column, ErrMissingField)
+			}
+			return 0, false
+		}
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			fail(column, fmt.Errorf("%w: %q", ErrNotInteger, s))
+			return 0, false
+		}
+		return i, true
+	}
+	// count parses a column that must not be negative.
+	count := func(column int, required bool) int64 {
+		i, ok := integer(column, required)
+		if ok && i < 0 {
+			fail(column, fmt.Errorf("%w: %d", ErrNegative, i))
+		}
+		return i
+	}
+
+	p.ProcessID, _ = integer(1, true)
+	if s, ok := field(2); ok && strings.Contains(s, ":") {
+		var err error
+		if p.Bursts, err = parseBursts(s); err != nil {
+			fail(2, err)
+		}
+		for _, b := range p.Bursts {
+			// Stop adding up once too long, before the total overflows.
+			if !b.IO && p.BurstDuration <= maxBurst {
+				p.BurstDuration += min64(b.Duration, maxBurst+1)
+			}
+		}
+	} else {
+		p.BurstDuration = count(2, true)
+	}
+	if p.BurstDuration > maxBurst {
+		fail(2, fmt.Errorf("%w: more than %d", ErrBurstRange, int64(maxBurst)))
+	}
+	p.ArrivalTime = count(3, true)
+	if priority, ok := integer(4, false); ok {
+		p.Priority = priority
+		if priority < minPriority || priority > maxPriority {
+			fail(4, fmt.Errorf("%w: %d", ErrPriorityRange, priority))
+		}
+	}
+	if class, ok := field(5); ok {
+		if classIndex(class) < 0 {
+			fail(5, fmt.Errorf("%w: %q", ErrUnknownClass, class))
+		}
+		p.Class = class
+	}
+	p.Tickets = count(6, false)
+	if p.Tickets > maxTickets {
+		fail(6, fmt.Errorf("%w: more than %d", ErrTicketRange, maxTickets))
+	}
+	p.Deadline = count(7, false)
+	p.Period = count(8, false)
+	if s, ok := field(9); ok {
+		var err error
+		if p.Affinity, err = parseAffinity(s); err != nil {
+			fail(9, err)
+		}
+	}
+	for column := len(workloadColumns) + 1; column <= len(row); column++ {
+		if _, ok := field(column); ok {
+			fail(column, ErrExtraField)
+		}
+	}
+	return p, problems
+}
+
+// parseBursts parses a sequence of CPU and I/O bursts such as
+// "cpu:5,io:3,cpu:2", separated by commas or semicolons, of which at least
+// one must be a CPU burst.
+func parseBursts(s string) ([]Burst, error) {
+	var (
+		bursts []Burst
+		cpu    bool
+	)
+	for _, field := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ';' }) {
+		kind, duration, _ := strings.Cut(strings.TrimSpace(field), ":")
+		d, err := strconv.ParseInt(duration, 10, 64)
+		if err != nil || d <= 0 || (kind != "cpu" && kind != "io") {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidBursts, s)
+		}
+		bursts = append(bursts, Burst{IO: kind == "io", Duration: d})
+		cpu = cpu || kind == "cpu"
+	}
+	if !cpu {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidBursts, s)
+	}
+	return bursts, nil
+}
+
+// parseAffinity parses a semicolon separated list of CPUs, such as "0;2",
+// into an affinity mask.
+func parseAffinity(s string) (uint64, error) {
+	var mask uint64
+	for _, field := range strings.Split(s, ";") {
+		cpu, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || cpu < 0 || cpu >= maxCPUs {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidAffinity, s)
+		}
+		mask |= 1 << cpu
+	}
+	return mask, nil
+}
+
 //endregion
@@ -110,6 +110,227 @@ func Test_loadProcesses(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "process classes",
+			args: args{
+				r: strings.NewReader(`1,5,0,2,system
+2,9,3,1,`),
+			},
+			want: []Process{
+				{
+					ProcessID:     1,
+					ArrivalTime:   0,
+					BurstDuration: 5,
+					Priority:      2,
+					Class:         "system",
+				},
+				{
+					ProcessID:     2,
+					ArrivalTime:   3,
+					BurstDuration: 9,
+					Priority:      1,
+				},
+			},
+		},
+		{
+			name: "deadlines and periods",
+			args: args{
+				r: strings.NewReader(`1,5,0,2,,,12,
+2,9,3,1,,,,20`),
+			},
+			want: []Process{
+				{
+					ProcessID:     1,
+					ArrivalTime:   0,
+					BurstDuration: 5,
+					Priority:      2,
+					Deadline:      12,
+				},
+				{
+					ProcessID:     2,
+					ArrivalTime:   3,
+					BurstDuration: 9,
+					Priority:      1,
+					Period:        20,
+				},
+			},
+		},
+		{
+			name: "affinity",
+			args: args{
+				r: strings.NewReader(`1,5,0,2,,,,,0;2
+2,9,3,1,,,,,`),
+			},
+			want: []Process{
+				{
+					ProcessID:     1,
+					ArrivalTime:   0,
+					BurstDuration: 5,
+					Priority:      2,
+					Affinity:      0b101,
+				},
+				{
+					ProcessID:     2,
+					ArrivalTime:   3,
+					BurstDuration: 9,
+					Priority:      1,
+				},
+			},
+		},
+		{
+			name: "burst sequences",
+			args: args{
+				r: strings.NewReader(`1,"cpu:5,io:3,cpu:2",0,2
+2,cpu:2;io:4,3,1`),
+			},
+			want: []Process{
+				{
+					ProcessID:     1,
+					ArrivalTime:   0,
+					BurstDuration: 7,
+					Priority:      2,
+					Bursts:        []Burst{{Duration: 5}, {IO: true, Duration: 3}, {Duration: 2}},
+				},
+				{
+					ProcessID:     2,
+					ArrivalTime:   3,
+					BurstDuration: 2,
+					Priority:      1,
+					Bursts:        []Burst{{Duration: 2}, {IO: true, Duration: 4}},
+				},
+			},
+		},
+		{
+			name: "bad burst sequence",
+			args: args{
+				r: strings.NewReader(`1,cpu:5;disk:3,0,2`),
+			},
+			wantErr: ErrInvalidBursts,
+		},
+		{
+			name: "no CPU burst",
+			args: args{
+				r: strings.NewReader(`1,io:3,0,2`),
+			},
+			wantErr: ErrInvalidBursts,
+		},
+		{
+			name: "bad affinity",
+			args: args{
+				r: strings.NewReader(`1,5,0,2,,,,,0;64`),
+			},
+			wantErr: ErrInvalidAffinity,
+		},
+		{
+			name: "unknown class",
+			args: args{
+				r: strings.NewReader(`1,5,0,2,realtime`),
+			},
+			wantErr: ErrUnknownClass,
+		},
+		{
+			name: "header and comments",
+			args: args{
+				r: strings.NewReader(`# example workload
+ID,Burst,Arrival,Priority
+1,5,0,2
+# the second process
+2, 9, 3`),
+			},
+			want: []Process{
+				{
+					ProcessID:     1,
+					ArrivalTime:   0,
+					BurstDuration: 5,
+					Priority:      2,
+				},
+				{
+					ProcessID:     2,
+					ArrivalTime:   3,
+					BurstDuration: 9,
+				},
+			},
+		},
+		{
+			name: "short row",
+			args: args{
+				r: strings.NewReader(`1,5`),
+			},
+			wantErr: ErrMissingField,
+		},
+		{
+			name: "not an integer",
+			args: args{
+				r: strings.NewReader(`1,5,0,2
+2,five,3,1`),
+			},
+			wantErr: ErrNotInteger,
+		},
+		{
+			name: "negative burst",
+			args: args{
+				r: strings.NewReader(`1,-5,0,2`),
+			},
+			wantErr: ErrNegative,
+		},
+		{
+			name: "duplicate IDs",
+			args: args{
+				r: strings.NewReader(`1,5,0,2
+1,9,3,1`),
+			},
+			wantErr: ErrDuplicateID,
+		},
+		{
+			name: "burst too long",
+			args: args{
+				r: strings.NewReader(`1,9000000000000000000,0,2`),
+			},
+			wantErr: ErrBurstRange,
+		},
+		{
+			name: "burst sequence too long",
+			args: args{
+				r: strings.NewReader(`1,cpu:9000000000000000000;io:1;cpu:9000000000000000000,0,2`),
+			},
+			wantErr: ErrBurstRange,
+		},
+		{
+			name: "too many tickets",
+			args: args{
+				r: strings.NewReader(`1,5,0,2,,5000000000000000000
+2,5,0,2,,5000000000000000000`),
+			},
+			wantErr: ErrTicketRange,
+		},
+		{
+			name: "priority out of range",
+			args: args{
+				r: strings.NewReader(`1,5,0,51`),
+			},
+			wantErr: ErrPriorityRange,
+		},
+		{
+			name: "too many columns",
+			args: args{
+				r: strings.NewReader(`1,5,0,2,,,,,,extra`),
+			},
+			wantErr: ErrExtraField,
+		},
+		{
+			name: "empty",
+			args: args{
+				r: strings.NewReader("# only a comment\n"),
+			},
+			wantErr: ErrEmptyWorkload,
+		},
+		{
+			name: "only a header",
+			args: args{
+				r: strings.NewReader("ID,Burst,Arrival,Priority\n"),
+			},
+			wantErr: ErrEmptyWorkload,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -126,82 +347,58 @@ func Test_loadProcesses(t *testing.T) {
 	}
 }
 
+func Test_loadProcesses_problems(t *testing.T) {
+	t.Parallel()
+	_, err := loadProcesses(strings.NewReader(`ID,Burst,Arrival,Priority
+1,5,0,2
+# a comment line still counts
+2,x,3,1
+1,-4,,0
+3
+"4,5,0`))
+	var problems LoadErrors
+	if !errors.As(err, &problems) {
+		t.Fatalf("loadProcesses() error = %v, want LoadErrors", err)
+	}
+	want := []string{
+		`line 4, column 2 (burst): not an integer: "x"`,
+		`line 5, column 1 (ID): duplicate process ID 1, first on line 2`,
+		`line 5, column 2 (burst): must not be negative: -4`,
+		`line 5, column 3 (arrival): missing field`,
+		`line 5, column 4 (priority): priority outside [1-50]: 0`,
+		`line 6, column 2 (burst): missing field`,
+		`line 6, column 3 (arrival): missing field`,
+		`line 7: extraneous or missing " in quoted-field`,
+	}
+	if got := err.Error(); got != strings.Join(want, "\n") {
+		t.Errorf("loadProcesses() error =\n%v\nwant\n%v", got, strings.Join(want, "\n"))
+	}
+}
+
 func loadFixture(t *testing.T, p ...string) string {
 	b, err := os.ReadFile(path.Join(p...))
 	if err != nil {
 		t.Fail()
 	}
 
-	return string(b)
+	// Fixtures may be checked out with Windows line endings.
+	return strings.ReplaceAll(string(b), "\r\n", "\n")
 }
 
-func Test_openProcessingFile1(t *testing.T) {
-	tmpFile, tErr := os.CreateTemp(t.TempDir(), "")
-	if tErr != nil {
-		t.Fatal(tErr)
+func Test_openWorkload(t *testing.T) {
+	t.Parallel()
+	workload := path.Join(t.TempDir(), "workload.csv")
+	if err := os.WriteFile(workload, []byte(exampleCSV), 0o600); err != nil {
+		t.Fatal(err)
 	}
-
-	type args struct {
-		args []string
+	f, err := openWorkload(workload)
+	if err != nil {
+		t.Fatalf("openWorkload() error = %v", err)
 	}
-	tests := []struct {
-		name    string
-		args    args
-		want    *os.File
-		wantErr bool
-	}{
-		{
-			name: "success",
-			args: args{
-				args: []string{"binary_name", tmpFile.Name()},
-			},
-			want: tmpFile,
-		},
-		{
-			name: "not enough args",
-			args: args{
-				args: []string{"binary_name"},
-			},
-			wantErr: true,
-		},
-		{
-			name: "bad file",
-			args: args{
-				args: []string{"binary_name", "bad_file_name"},
-			},
-			wantErr: true,
-		},
+	if err := f.Close(); err != nil {
+		t.Error(err)
 	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got, closeFn, err := openProcessingFile(tt.args.args...)
-			if (err != nil) != tt.wantErr {
-				t.Fatalf("openProcessingFile() error = %v, wantErr %v", err, tt.wantErr)
-			}
-			if err != nil {
-				return
-			}
-
-			if got == nil {
-				t.Fatal("file is unexpectedly nil")
-			}
-			if closeFn == nil {
-				t.Fatal("closeFn is unexpectedly nil")
-			}
-			t.Cleanup(closeFn)
-
-			f1, err := os.Stat(got.Name())
-			if err != nil {
-				t.Fatalf("Could not stat file: %v", got)
-			}
-			f2, err := os.Stat(tt.want.Name())
-			if err != nil {
-				t.Fatalf("Could not stat file: %v", tt.want)
-			}
-
-			if !os.SameFile(f1, f2) {
-				t.Fatal("files are not the same")
-			}
-		})
+	if _, err := openWorkload(path.Join(t.TempDir(), "missing.csv")); err == nil {
+		t.Error("openWorkload() of a missing file succeeded")
 	}
 }
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+)
+
+func init() {
+	Register("mlfq", newMLFQ)
+}
+
+// mlfq is a multilevel feedback queue. New processes enter the top level;
+// a process that uses up its quantum is demoted a level, and every boost
+// ticks all processes are moved back to the top. Higher levels preempt
+// lower ones, and each level is round-robin with its own quantum.
+type mlfq struct {
+	machine
+	quanta []int64
+	boost  int64
+}
+
+// newMLFQ builds an MLFQ from the parameters:
+// • levels: number of queues (default 3)
+// • quantum: quantum of the top level, doubling at each level below (default 2)
+// • quantumN: quantum of level N, overriding the doubling
+// • boost: ticks between priority boosts, 0 to never boost (default 50)
+func newMLFQ(params Parameters) (Scheduler, error) {
+	levels, err := params.positive("levels", 3)
+	if err != nil {
+		return nil, err
+	}
+	base, err := params.positive("quantum", 2)
+	if err != nil {
+		return nil, err
+	}
+	m, err := newMachine(params)
+	if err != nil {
+		return nil, err
+	}
+	s := mlfq{machine: m, quanta: make([]int64, levels), boost: params.Get("boost", 50)}
+	if s.boost < 0 {
+		return nil, fmt.Errorf("%w: boost must not be negative, got %d", ErrInvalidParameter, s.boost)
+	}
+	for i := range s.quanta {
+		if s.quanta[i], err = params.positive("quantum"+strconv.Itoa(i), base<<i); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func (mlfq) Name() string  { return "mlfq" }
+func (mlfq) Title() string { return "Multilevel feedback queue" }
+
+func (s mlfq) Parameters() Parameters {
+	params := Parameters{"levels": int64(len(s.quanta)), "boost": s.boost}
+	for i, q := range s.quanta {
+		params["quantum"+strconv.Itoa(i)] = q
+	}
+	return s.parameters(params)
+}
+
+func (s mlfq) Schedule(processes []Process) Result {
+	// Processes keep their level when they move between CPUs.
+	level := make(map[*task]int, len(processes))
+	return s.simulate(processes, func() policy {
+		return &mlfqPolicy{mlfq: s, queues: make([]fifo, len(s.quanta)), level: level}
+	})
+}
+
+// mlfqPolicy is the simulation state of an mlfq.
+type mlfqPolicy struct {
+	mlfq
+	queues []fifo
+	level  map[*task]int
+}
+
+func (p *mlfqPolicy) push(t *task, _ int64) {
+	p.queues[p.level[t]].push(t)
+}
+
+func (p *mlfqPolicy) pop(int64) (*task, int64) {
+	for i := range p.queues {
+		if len(p.queues[i]) > 0 {
+			return p.queues[i].pop(), p.quanta[i]
+		}
+	}
+	return nil, 0
+}
+
+func (p *mlfqPolicy) empty() bool {
+	for i := range p.queues {
+		if len(p.queues[i]) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *mlfqPolicy) ran(t *task, _ int64, expired bool) {
+	if expired && p.level[t] < len(p.queues)-1 {
+		p.level[t]++
+	}
+}
+
+// preempts reports whether a process is waiting at a higher level than the
+// running one.
+func (p *mlfqPolicy) preempts(running *task, _ int64) bool {
+	for i := 0; i < p.level[running]; i++ {
+		if len(p.queues[i]) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *mlfqPolicy) nextTimer(now int64) (int64, bool) {
+	if p.boost == 0 {
+		return 0, false
+	}
+	return (now/p.boost + 1) * p.boost, true
+}
+
+// fire boosts every process to the top level, keeping their order.
+func (p *mlfqPolicy) fire(running *task, _ int64) {
+	for i := 1; i < len(p.queues); i++ {
+		for len(p.queues[i]) > 0 {
+			t := p.queues[i].pop()
+			p.level[t] = 0
+			p.queues[0].push(t)
+		}
+	}
+	if running != nil {
+		p.level[running] = 0
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMLFQ_Schedule(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		params         Parameters
+		processes      []Process
+		wantGantt      []TimeSlice
+		wantCompletion []int64
+	}{
+		{
+			name:      "demotion and preemption",
+			processes: exampleProcesses,
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 2},
+				{PID: 1, Start: 2, Stop: 3},
+				{PID: 2, Start: 3, Stop: 5},
+				{PID: 1, Start: 5, Stop: 6},
+				{PID: 3, Start: 6, Stop: 8},
+				{PID: 2, Start: 8, Stop: 12},
+				{PID: 1, Start: 12, Stop: 13},
+				{PID: 3, Start: 13, Stop: 17},
+				{PID: 2, Start: 17, Stop: 20},
+			},
+			wantCompletion: []int64{13, 20, 17},
+		},
+		{
+			name:      "no boost",
+			params:    Parameters{"levels": 2, "quantum": 1, "boost": 0},
+			processes: []Process{{ProcessID: 1, BurstDuration: 10}},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 1},
+				{PID: 1, Start: 1, Stop: 3},
+				{PID: 1, Start: 3, Stop: 5},
+				{PID: 1, Start: 5, Stop: 7},
+				{PID: 1, Start: 7, Stop: 9},
+				{PID: 1, Start: 9, Stop: 10},
+			},
+			wantCompletion: []int64{10},
+		},
+		{
+			name:      "boost resets to top level",
+			params:    Parameters{"levels": 2, "quantum": 1, "boost": 5},
+			processes: []Process{{ProcessID: 1, BurstDuration: 10}},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 1},
+				{PID: 1, Start: 1, Stop: 3},
+				{PID: 1, Start: 3, Stop: 5},
+				{PID: 1, Start: 5, Stop: 6},
+				{PID: 1, Start: 6, Stop: 8},
+				{PID: 1, Start: 8, Stop: 10},
+			},
+			wantCompletion: []int64{10},
+		},
+		{
+			name:      "idle gap",
+			processes: []Process{{ProcessID: 1, BurstDuration: 1}, {ProcessID: 2, BurstDuration: 1, ArrivalTime: 4}},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 1},
+				{PID: 2, Start: 4, Stop: 5},
+			},
+			wantCompletion: []int64{1, 5},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s, err := NewScheduler("mlfq", tt.params)
+			if err != nil {
+				t.Fatalf("NewScheduler() unexpected error: %v", err)
+			}
+			got := s.Schedule(tt.processes)
+			if !reflect.DeepEqual(got.Gantt, tt.wantGantt) {
+				t.Errorf("Gantt = %v, want %v", got.Gantt, tt.wantGantt)
+			}
+			for i, row := range got.Processes {
+				if row.Completion != tt.wantCompletion[i] {
+					t.Errorf("process %d completion = %d, want %d", row.ProcessID, row.Completion, tt.wantCompletion[i])
+				}
+			}
+		})
+	}
+}
+
+func Test_newMLFQ(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		params  Parameters
+		want    Parameters
+		wantErr error
+	}{
+		{
+			name: "defaults",
+			want: Parameters{"levels": 3, "boost": 50, "quantum0": 2, "quantum1": 4, "quantum2": 8},
+		},
+		{
+			name:   "per-level quantum",
+			params: Parameters{"levels": 2, "quantum": 3, "quantum1": 10},
+			want:   Parameters{"levels": 2, "boost": 50, "quantum0": 3, "quantum1": 10},
+		},
+		{
+			name:    "no levels",
+			params:  Parameters{"levels": 0},
+			wantErr: ErrInvalidParameter,
+		},
+		{
+			name:    "negative boost",
+			params:  Parameters{"boost": -1},
+			wantErr: ErrInvalidParameter,
+		},
+		{
+			name:    "bad level quantum",
+			params:  Parameters{"quantum2": 0},
+			wantErr: ErrInvalidParameter,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := newMLFQ(tt.params)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("newMLFQ() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got.Parameters(), tt.want) {
+				t.Errorf("Parameters() = %v, want %v", got.Parameters(), tt.want)
+			}
+		})
+	}
+}
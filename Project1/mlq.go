@@ -0,0 +1,194 @@
+package main
+
+import "fmt"
+
+// processClasses are the multilevel queue classes, highest priority first.
+// Processes without a class are batch work.
+var processClasses = []string{"system", "interactive", "batch"}
+
+// classIndex returns the queue of a process class, or -1 if it is unknown.
+func classIndex(class string) int {
+	if class == "" {
+		return len(processClasses) - 1
+	}
+	for i, c := range processClasses {
+		if c == class {
+			return i
+		}
+	}
+	return -1
+}
+
+func init() {
+	Register("mlq", newMLQ)
+}
+
+// mlq is a static multilevel queue: each process class has its own queue,
+// scheduled FCFS or round-robin. Queues either have fixed priority, a higher
+// class preempting a lower one, or take turns at the CPU for a number of
+// ticks each.
+type mlq struct {
+	machine
+	timesliced bool
+	quantum    []int64
+	share      []int64
+}
+
+// newMLQ builds a multilevel queue from the parameters:
+// • timesliced: 1 to share the CPU between queues, 0 for fixed priority (default 0)
+// • <class>_quantum: round-robin quantum of a class, 0 for FCFS (default interactive 2, others 0)
+// • <class>_share: ticks per turn of a class when time-sliced (default system 4, interactive 2, batch 1)
+func newMLQ(params Parameters) (Scheduler, error) {
+	m, err := newMachine(params)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		defaultQuantum = []int64{0, 2, 0}
+		defaultShare   = []int64{4, 2, 1}
+		s              = mlq{
+			machine:    m,
+			timesliced: params.Get("timesliced", 0) != 0,
+			quantum:    make([]int64, len(processClasses)),
+			share:      make([]int64, len(processClasses)),
+		}
+	)
+	for i, class := range processClasses {
+		s.quantum[i] = params.Get(class+"_quantum", defaultQuantum[i])
+		if s.quantum[i] < 0 {
+			return nil, fmt.Errorf("%w: %s_quantum must not be negative, got %d", ErrInvalidParameter, class, s.quantum[i])
+		}
+		if s.share[i], err = params.positive(class+"_share", defaultShare[i]); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func (mlq) Name() string { return "mlq" }
+
+func (s mlq) Title() string {
+	if s.timesliced {
+		return "Multilevel queue (time-sliced)"
+	}
+	return "Multilevel queue (fixed priority)"
+}
+
+func (s mlq) Parameters() Parameters {
+	params := Parameters{"timesliced": 0}
+	if s.timesliced {
+		params["timesliced"] = 1
+	}
+	for i, class := range processClasses {
+		params[class+"_quantum"] = s.quantum[i]
+		params[class+"_share"] = s.share[i]
+	}
+	return s.parameters(params)
+}
+
+func (s mlq) Schedule(processes []Process) Result {
+	return s.simulate(processes, func() policy {
+		return &mlqPolicy{
+			mlq:     s,
+			queues:  make([]fifo, len(processClasses)),
+			running: make(map[*task]int64),
+			// The first turn goes to the highest class.
+			current: len(processClasses) - 1,
+		}
+	})
+}
+
+// mlqPolicy is the simulation state of an mlq. With several CPUs sharing
+// one run queue the queues and turns are global: every CPU takes from the
+// queue whose turn it is, and the ticks each runs count against that turn.
+type mlqPolicy struct {
+	mlq
+	queues []fifo
+	// current is the queue whose turn it is when time-sliced, and shareLeft
+	// the ticks left in that turn.
+	current   int
+	shareLeft int64
+	// running holds the ticks of its round-robin quantum each task on a CPU
+	// has left.
+	running map[*task]int64
+}
+
+// push queues t behind its class. A process taken off the CPU before its
+// quantum ran out resumes at the head of its queue, so FCFS queues stay FCFS.
+func (p *mlqPolicy) push(t *task, _ int64) {
+	q := &p.queues[classIndex(t.Class)]
+	if quantumLeft, ok := p.running[t]; ok {
+		delete(p.running, t)
+		if p.quantum[classIndex(t.Class)] == 0 || quantumLeft > 0 {
+			q.pushFront(t)
+			return
+		}
+	}
+	q.push(t)
+}
+
+func (p *mlqPolicy) pop(int64) (*task, int64) {
+	i := p.nextQueue()
+	t := p.queues[i].pop()
+	p.running[t] = p.quantum[i]
+
+	slice := p.quantum[i]
+	if p.timesliced && (slice == 0 || p.shareLeft < slice) {
+		slice = p.shareLeft
+	}
+	return t, slice
+}
+
+// nextQueue returns the queue to run from: the highest non-empty one, or when
+// time-sliced the current one until its turn is up, then the next non-empty
+// one in order.
+func (p *mlqPolicy) nextQueue() int {
+	if !p.timesliced {
+		for i := range p.queues {
+			if len(p.queues[i]) > 0 {
+				return i
+			}
+		}
+	}
+	if p.shareLeft <= 0 || len(p.queues[p.current]) == 0 {
+		for i := 1; i <= len(p.queues); i++ {
+			if next := (p.current + i) % len(p.queues); len(p.queues[next]) > 0 {
+				p.current = next
+				break
+			}
+		}
+		p.shareLeft = p.share[p.current]
+	}
+	return p.current
+}
+
+func (p *mlqPolicy) empty() bool {
+	for i := range p.queues {
+		if len(p.queues[i]) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *mlqPolicy) ran(t *task, d int64, _ bool) {
+	p.shareLeft -= d
+	if t.remaining == 0 {
+		delete(p.running, t)
+		return
+	}
+	p.running[t] -= d
+}
+
+// preempts reports whether a higher class is waiting under fixed priority.
+func (p *mlqPolicy) preempts(running *task, _ int64) bool {
+	if p.timesliced {
+		return false
+	}
+	for i := 0; i < classIndex(running.Class); i++ {
+		if len(p.queues[i]) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMLQ_Schedule(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 4, ArrivalTime: 0, Class: "batch"},
+		{ProcessID: 2, BurstDuration: 3, ArrivalTime: 1, Class: "interactive"},
+		{ProcessID: 3, BurstDuration: 2, ArrivalTime: 2, Class: "system"},
+		{ProcessID: 4, BurstDuration: 2, ArrivalTime: 1, Class: "interactive"},
+	}
+	tests := []struct {
+		name           string
+		params         Parameters
+		wantTitle      string
+		wantGantt      []TimeSlice
+		wantCompletion []int64
+	}{
+		{
+			name:      "fixed priority",
+			wantTitle: "Multilevel queue (fixed priority)",
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 1},
+				{PID: 2, Start: 1, Stop: 2},
+				{PID: 3, Start: 2, Stop: 4},
+				{PID: 2, Start: 4, Stop: 6},
+				{PID: 4, Start: 6, Stop: 8},
+				{PID: 1, Start: 8, Stop: 11},
+			},
+			wantCompletion: []int64{11, 6, 4, 8},
+		},
+		{
+			name:      "time-sliced",
+			params:    Parameters{"timesliced": 1},
+			wantTitle: "Multilevel queue (time-sliced)",
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 1},
+				{PID: 2, Start: 1, Stop: 3},
+				{PID: 1, Start: 3, Stop: 4},
+				{PID: 3, Start: 4, Stop: 6},
+				{PID: 4, Start: 6, Stop: 8},
+				{PID: 1, Start: 8, Stop: 9},
+				{PID: 2, Start: 9, Stop: 10},
+				{PID: 1, Start: 10, Stop: 11},
+			},
+			wantCompletion: []int64{11, 10, 6, 8},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s, err := NewScheduler("mlq", tt.params)
+			if err != nil {
+				t.Fatalf("NewScheduler() unexpected error: %v", err)
+			}
+			if s.Title() != tt.wantTitle {
+				t.Errorf("Title() = %v, want %v", s.Title(), tt.wantTitle)
+			}
+			got := s.Schedule(processes)
+			if !reflect.DeepEqual(got.Gantt, tt.wantGantt) {
+				t.Errorf("Gantt = %v, want %v", got.Gantt, tt.wantGantt)
+			}
+			for i, row := range got.Processes {
+				if row.Completion != tt.wantCompletion[i] {
+					t.Errorf("process %d completion = %d, want %d", row.ProcessID, row.Completion, tt.wantCompletion[i])
+				}
+			}
+		})
+	}
+}
+
+// TestMLQ_Schedule_twoCPUs pins the CPUs sharing the queues and turns of one
+// run queue, while each task keeps the quantum it has left.
+func TestMLQ_Schedule_twoCPUs(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 4, ArrivalTime: 0, Class: "batch"},
+		{ProcessID: 2, BurstDuration: 3, ArrivalTime: 1, Class: "interactive"},
+		{ProcessID: 3, BurstDuration: 2, ArrivalTime: 2, Class: "system"},
+		{ProcessID: 4, BurstDuration: 3, ArrivalTime: 1, Class: "interactive"},
+		{ProcessID: 5, BurstDuration: 3, ArrivalTime: 1, Class: "interactive"},
+	}
+	tests := []struct {
+		name      string
+		params    Parameters
+		wantGantt []TimeSlice
+	}{
+		{
+			// Process 2, preempted with a tick of its quantum left, resumes
+			// ahead of process 5 although process 4 was popped after it.
+			name:   "fixed priority",
+			params: Parameters{"cpus": 2},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 1},
+				{PID: 2, Start: 1, Stop: 2},
+				{PID: 4, Start: 1, Stop: 3, CPU: 1},
+				{PID: 3, Start: 2, Stop: 4},
+				{PID: 2, Start: 3, Stop: 5, CPU: 1},
+				{PID: 5, Start: 4, Stop: 6},
+				{PID: 4, Start: 5, Stop: 6, CPU: 1},
+				{PID: 5, Start: 6, Stop: 7},
+				{PID: 1, Start: 6, Stop: 9, CPU: 1},
+			},
+		},
+		{
+			// Both CPUs take from the queue whose turn it is.
+			name:   "time-sliced",
+			params: Parameters{"cpus": 2, "timesliced": 1},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 1},
+				{PID: 2, Start: 1, Stop: 3},
+				{PID: 4, Start: 1, Stop: 3, CPU: 1},
+				{PID: 1, Start: 3, Stop: 4},
+				{PID: 3, Start: 3, Stop: 5, CPU: 1},
+				{PID: 5, Start: 4, Stop: 6},
+				{PID: 1, Start: 5, Stop: 6, CPU: 1},
+				{PID: 2, Start: 6, Stop: 7},
+				{PID: 4, Start: 6, Stop: 7, CPU: 1},
+				{PID: 1, Start: 7, Stop: 8},
+				{PID: 5, Start: 7, Stop: 8, CPU: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s, err := NewScheduler("mlq", tt.params)
+			if err != nil {
+				t.Fatalf("NewScheduler() unexpected error: %v", err)
+			}
+			if got := s.Schedule(processes); !reflect.DeepEqual(got.Gantt, tt.wantGantt) {
+				t.Errorf("Gantt = %v, want %v", got.Gantt, tt.wantGantt)
+			}
+		})
+	}
+}
+
+func Test_newMLQ(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		params  Parameters
+		wantErr error
+	}{
+		{name: "defaults"},
+		{name: "negative quantum", params: Parameters{"batch_quantum": -1}, wantErr: ErrInvalidParameter},
+		{name: "zero share", params: Parameters{"system_share": 0}, wantErr: ErrInvalidParameter},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := newMLQ(tt.params); !errors.Is(err, tt.wantErr) {
+				t.Errorf("newMLQ() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ErrInvalidPeriod is returned for periodic tasks that cannot be expanded.
+var ErrInvalidPeriod = errors.New("invalid periodic task")
+
+// maxJobs limits how many jobs the periodic tasks of a workload may release
+// over their hyperperiod.
+const maxJobs = 1 << 20
+
+// expandPeriodic replaces every periodic task with the jobs it releases over
+// one hyperperiod of all the tasks from its offset, after which the releases
+// repeat. Each job's burst is the task's worst-case execution
+// time and its deadline is its release plus the task's relative deadline,
+// which defaults to the period. The result is ordered by release time.
+func expandPeriodic(processes []Process) ([]Process, error) {
+	var periods []int64
+	for _, p := range processes {
+		if p.Period == 0 {
+			continue
+		}
+		if p.Period < 0 || p.BurstDuration <= 0 || p.Deadline < 0 ||
+			p.BurstDuration > relativeDeadline(p) || relativeDeadline(p) > p.Period {
+			return nil, fmt.Errorf("%w: process %d needs 0 < WCET %d <= deadline %d <= period %d",
+				ErrInvalidPeriod, p.ProcessID, p.BurstDuration, relativeDeadline(p), p.Period)
+		}
+		periods = append(periods, p.Period)
+	}
+	if len(periods) == 0 {
+		return processes, nil
+	}
+	h, ok := hyperperiod(periods)
+	if !ok {
+		return nil, fmt.Errorf("%w: hyperperiod overflows", ErrInvalidPeriod)
+	}
+	var jobs int64
+	for _, period := range periods {
+		if jobs += h / period; jobs > maxJobs {
+			return nil, fmt.Errorf("%w: more than %d jobs in the hyperperiod %d", ErrInvalidPeriod, maxJobs, h)
+		}
+	}
+
+	out := make([]Process, 0, len(processes)-len(periods)+int(jobs))
+	for _, p := range processes {
+		if p.Period == 0 {
+			out = append(out, p)
+			continue
+		}
+		deadline := relativeDeadline(p)
+		for k, release := int64(1), p.ArrivalTime; release < p.ArrivalTime+h; k, release = k+1, release+p.Period {
+			job := p
+			job.Job = k
+			job.ArrivalTime = release
+			job.Deadline = release + deadline
+			out = append(out, job)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].ArrivalTime < out[j].ArrivalTime })
+	return out, nil
+}
+
+// relativeDeadline returns the deadline of each job of a periodic task
+// relative to its release.
+func relativeDeadline(p Process) int64 {
+	if p.Deadline > 0 {
+		return p.Deadline
+	}
+	return p.Period
+}
+
+// hyperperiod returns the least common multiple of periods, or false if it
+// does not fit in an int64.
+func hyperperiod(periods []int64) (int64, bool) {
+	h := int64(1)
+	for _, p := range periods {
+		m := p / gcd(h, p)
+		if h > math.MaxInt64/m {
+			return 0, false
+		}
+		h *= m
+	}
+	return h, true
+}
+
+func gcd(a, b int64) int64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// periodicTasks recovers the periodic tasks from their expanded jobs, with
+// their first release as arrival time and relative deadline.
+func periodicTasks(processes []Process) []Process {
+	var tasks []Process
+	for _, p := range processes {
+		if p.Period > 0 && p.Job == 1 {
+			p.Deadline -= p.ArrivalTime
+			p.Job = 0
+			tasks = append(tasks, p)
+		}
+	}
+	return tasks
+}
+
+// analyse checks whether tasks, listed highest priority first, always meet
+// their deadlines under preemptive fixed-priority scheduling. Each task's
+// worst-case response time R is the least fixed point of
+//
+//	R = C + Σ ⌈R / Tj⌉ Cj
+//
+// over the higher priority tasks j, taking every task to be released at
+// once.
+func analyse(tasks []Process) *Analysis {
+	a := &Analysis{Tasks: make([]TaskAnalysis, len(tasks)), Schedulable: true}
+	if n := float64(len(tasks)); n > 0 {
+		a.Bound = n * (math.Pow(2, 1/n) - 1)
+	}
+	for i, t := range tasks {
+		ta := TaskAnalysis{
+			PID:         t.ProcessID,
+			Period:      t.Period,
+			WCET:        t.BurstDuration,
+			Deadline:    relativeDeadline(t),
+			Utilisation: float64(t.BurstDuration) / float64(t.Period),
+		}
+		a.Utilisation += ta.Utilisation
+
+		r := t.BurstDuration
+		for _, hp := range tasks[:i] {
+			r += hp.BurstDuration
+		}
+		for r <= ta.Deadline {
+			next := t.BurstDuration
+			for _, hp := range tasks[:i] {
+				next += (r + hp.Period - 1) / hp.Period * hp.BurstDuration
+			}
+			if next == r {
+				break
+			}
+			r = next
+		}
+		ta.Response = r
+		ta.Schedulable = r <= ta.Deadline
+		a.Schedulable = a.Schedulable && ta.Schedulable
+		a.Tasks[i] = ta
+	}
+	return a
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func Test_expandPeriodic(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		processes []Process
+		want      []Process
+		wantErr   error
+	}{
+		{
+			name:      "no periodic tasks",
+			processes: []Process{{ProcessID: 1, BurstDuration: 3}},
+			want:      []Process{{ProcessID: 1, BurstDuration: 3}},
+		},
+		{
+			name: "jobs over the hyperperiod",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 1, ArrivalTime: 1, Period: 2},
+				{ProcessID: 2, BurstDuration: 2, Period: 3, Deadline: 2},
+				{ProcessID: 3, BurstDuration: 4, ArrivalTime: 2},
+			},
+			want: []Process{
+				{ProcessID: 2, BurstDuration: 2, Period: 3, Deadline: 2, Job: 1},
+				{ProcessID: 1, BurstDuration: 1, ArrivalTime: 1, Period: 2, Deadline: 3, Job: 1},
+				{ProcessID: 3, BurstDuration: 4, ArrivalTime: 2},
+				{ProcessID: 1, BurstDuration: 1, ArrivalTime: 3, Period: 2, Deadline: 5, Job: 2},
+				{ProcessID: 2, BurstDuration: 2, ArrivalTime: 3, Period: 3, Deadline: 5, Job: 2},
+				{ProcessID: 1, BurstDuration: 1, ArrivalTime: 5, Period: 2, Deadline: 7, Job: 3},
+			},
+		},
+		{
+			name:      "WCET longer than the deadline",
+			processes: []Process{{ProcessID: 1, BurstDuration: 3, Period: 4, Deadline: 2}},
+			wantErr:   ErrInvalidPeriod,
+		},
+		{
+			name: "too many jobs",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 1, Period: 1},
+				{ProcessID: 2, BurstDuration: 1, Period: 2 * maxJobs},
+			},
+			wantErr: ErrInvalidPeriod,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := expandPeriodic(tt.processes)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expandPeriodic() = %v, want %v", got, tt.want)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_hyperperiod(t *testing.T) {
+	t.Parallel()
+	if got, ok := hyperperiod([]int64{4, 6, 10}); got != 60 || !ok {
+		t.Errorf("hyperperiod(4, 6, 10) = %d, %v, want 60, true", got, ok)
+	}
+	if _, ok := hyperperiod([]int64{math.MaxInt64, 2}); ok {
+		t.Errorf("hyperperiod(MaxInt64, 2) did not overflow")
+	}
+}
+
+func Test_analyse(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		tasks []Process
+		want  []int64
+		ok    bool
+	}{
+		{
+			name: "under the bound",
+			tasks: []Process{
+				{ProcessID: 1, BurstDuration: 1, Period: 4},
+				{ProcessID: 2, BurstDuration: 2, Period: 6},
+			},
+			want: []int64{1, 3},
+			ok:   true,
+		},
+		{
+			name: "over the bound but schedulable",
+			tasks: []Process{
+				{ProcessID: 1, BurstDuration: 1, Period: 4},
+				{ProcessID: 2, BurstDuration: 2, Period: 6},
+				{ProcessID: 3, BurstDuration: 3, Period: 12},
+			},
+			want: []int64{1, 3, 10},
+			ok:   true,
+		},
+		{
+			name: "unschedulable",
+			tasks: []Process{
+				{ProcessID: 1, BurstDuration: 2, Period: 4},
+				{ProcessID: 2, BurstDuration: 3, Period: 5},
+			},
+			want: []int64{2, 7},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := analyse(tt.tasks)
+			for i, task := range got.Tasks {
+				if task.Response != tt.want[i] {
+					t.Errorf("task %d response = %d, want %d", task.PID, task.Response, tt.want[i])
+				}
+			}
+			if got.Schedulable != tt.ok {
+				t.Errorf("Schedulable = %v, want %v", got.Schedulable, tt.ok)
+			}
+			if wantBound := float64(len(tt.tasks)) * (math.Pow(2, 1/float64(len(tt.tasks))) - 1); got.Bound != wantBound {
+				t.Errorf("Bound = %v, want %v", got.Bound, wantBound)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"io"
+)
+
+func init() {
+	Register("priority", func(params Parameters) (Scheduler, error) {
+		a, err := newAging(params)
+		if err != nil {
+			return nil, err
+		}
+		m, err := newMachine(params)
+		return priority{a, m}, err
+	})
+	Register("priority-np", func(params Parameters) (Scheduler, error) {
+		a, err := newAging(params)
+		if err != nil {
+			return nil, err
+		}
+		m, err := newMachine(params)
+		return priorityNP{a, m}, err
+	})
+}
+
+// priority runs the arrived process with the highest priority (lowest
+// number), breaking ties by shortest remaining time, and preempts on arrival.
+type priority struct {
+	aging
+	machine
+}
+
+func (priority) Name() string             { return "priority" }
+func (priority) Title() string            { return "Priority (preemptive)" }
+func (s priority) Parameters() Parameters { return s.parameters(s.aging.Parameters()) }
+
+// Schedule needs the aging policy's scan of the ready processes only when
+// their priorities age.
+func (s priority) Schedule(processes []Process) Result {
+	if s.step > 0 {
+		return s.schedule(processes, true, s.machine)
+	}
+	return s.simulate(processes, orderBy(higherPriorityRemaining, true))
+}
+
+// priorityNP runs the arrived process with the highest priority to
+// completion, breaking ties by shortest burst.
+type priorityNP struct {
+	aging
+	machine
+}
+
+func (priorityNP) Name() string             { return "priority-np" }
+func (priorityNP) Title() string            { return "Priority (non-preemptive)" }
+func (s priorityNP) Parameters() Parameters { return s.parameters(s.aging.Parameters()) }
+
+func (s priorityNP) Schedule(processes []Process) Result {
+	if s.step > 0 {
+		return s.schedule(processes, false, s.machine)
+	}
+	return s.simulate(processes, orderBy(higherPriority, false))
+}
+
+// higherPriorityRemaining orders tasks by priority, then remaining time, then
+// arrival.
+func higherPriorityRemaining(a, b *task) bool {
+	if a.Priority != b.Priority {
+		return a.Priority < b.Priority
+	}
+	return shorterRemaining(a, b)
+}
+
+// higherPriority orders tasks by priority, then burst, then arrival.
+func higherPriority(a, b *task) bool {
+	if a.Priority != b.Priority {
+		return a.Priority < b.Priority
+	}
+	return shorterJob(a, b)
+}
+
+// Implement SJF priority scheduling (preemptive) and report average turnaround time, average waiting time, and average throughput.
+func SJFPrioritySchedule(w io.Writer, title string, processes []Process) {
+	outputResult(w, title, priority{}.Schedule(processes))
+}
@@ -0,0 +1,66 @@
+package main
+
+import "sort"
+
+// tickets returns the proportional share of a process: its Tickets column if
+// set, otherwise one ticket per step of priority above the lowest (50).
+func tickets(p Process) int64 {
+	switch {
+	case p.Tickets > 0:
+		return p.Tickets
+	case p.Priority >= 1 && p.Priority <= 50:
+		return 51 - p.Priority
+	default:
+		return 1
+	}
+}
+
+// shares compares the CPU each process received between its arrival and
+// completion with the share its tickets entitled it to, given the tickets of
+// every process runnable alongside it.
+func shares(rows []ProcessResult) []Share {
+	// Total tickets runnable changes only on arrivals and completions.
+	type event struct {
+		at    int64
+		delta int64
+	}
+	events := make([]event, 0, 2*len(rows))
+	for _, row := range rows {
+		events = append(events,
+			event{at: row.ArrivalTime, delta: tickets(row.Process)},
+			event{at: row.Completion, delta: -tickets(row.Process)})
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].at < events[j].at })
+
+	// integral[k] is the integral of 1/(runnable tickets) from the first
+	// event up to times[k].
+	var (
+		times    = make([]int64, 0, len(events))
+		integral = make([]float64, 0, len(events))
+		total    int64
+		sum      float64
+	)
+	for i, e := range events {
+		if i > 0 && e.at > events[i-1].at && total > 0 {
+			sum += float64(e.at-events[i-1].at) / float64(total)
+		}
+		if len(times) == 0 || times[len(times)-1] != e.at {
+			times = append(times, e.at)
+			integral = append(integral, sum)
+		}
+		total += e.delta
+	}
+	at := func(t int64) float64 {
+		return integral[sort.Search(len(times), func(i int) bool { return times[i] >= t })]
+	}
+
+	out := make([]Share, len(rows))
+	for i, row := range rows {
+		out[i] = Share{PID: row.ProcessID, Tickets: tickets(row.Process)}
+		if life := row.Completion - row.ArrivalTime; life > 0 {
+			out[i].Expected = float64(out[i].Tickets) * (at(row.Completion) - at(row.ArrivalTime)) / float64(life)
+			out[i].Received = float64(row.BurstDuration) / float64(life)
+		}
+	}
+	return out
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func Test_tickets(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		p    Process
+		want int64
+	}{
+		{name: "tickets column", p: Process{Priority: 1, Tickets: 7}, want: 7},
+		{name: "highest priority", p: Process{Priority: 1}, want: 50},
+		{name: "lowest priority", p: Process{Priority: 50}, want: 1},
+		{name: "no priority", p: Process{}, want: 1},
+	}
+	for _, tt := range tests {
+		if got := tickets(tt.p); got != tt.want {
+			t.Errorf("%s: tickets() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func Test_shares(t *testing.T) {
+	t.Parallel()
+	rows := []ProcessResult{
+		// Runs alone for 2 ticks, then shares 3:1 with process 2 until 10.
+		{Process: Process{ProcessID: 1, BurstDuration: 8, Tickets: 3}, Completion: 10},
+		{Process: Process{ProcessID: 2, BurstDuration: 4, ArrivalTime: 2, Tickets: 1}, Completion: 12},
+	}
+	want := []Share{
+		{PID: 1, Tickets: 3, Expected: (2 + 8*0.75) / 10, Received: 0.8},
+		{PID: 2, Tickets: 1, Expected: (8*0.25 + 2) / 10, Received: 0.4},
+	}
+	got := shares(rows)
+	for i := range got {
+		if math.Abs(got[i].Expected-want[i].Expected) > 1e-9 {
+			t.Errorf("shares()[%d].Expected = %v, want %v", i, got[i].Expected, want[i].Expected)
+		}
+		got[i].Expected = want[i].Expected
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("shares() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+func init() {
+	formats["html"] = writeHTML
+}
+
+type (
+	// reportRun is a run as shown in the HTML report.
+	reportRun struct {
+		Run
+		Chart template.HTML
+	}
+
+	// reportWinner is the best algorithm for one metric.
+	reportWinner struct {
+		Metric string
+		Titles string
+		Value  string
+	}
+)
+
+// bestBy returns the titles of the runs with the best value of metric, where
+// better means lower unless higher is set, and that value.
+func bestBy(runs []Run, metric func(Stats) float64, higher bool) ([]string, float64) {
+	var (
+		titles []string
+		best   float64
+	)
+	for i, run := range runs {
+		v := metric(run.Stats)
+		switch {
+		case i == 0 || (higher && v > best) || (!higher && v < best):
+			titles, best = []string{run.Title}, v
+		case v == best:
+			titles = append(titles, run.Title)
+		}
+	}
+	return titles, best
+}
+
+// winners summarises which runs did best on wait, turnaround and throughput.
+func winners(runs []Run) []reportWinner {
+	metrics := []struct {
+		name   string
+		metric func(Stats) float64
+		higher bool
+		unit   string
+	}{
+		{name: "Average wait", metric: func(s Stats) float64 { return s.AverageWait }},
+		{name: "Average turnaround", metric: func(s Stats) float64 { return s.AverageTurnaround }},
+		{name: "Throughput", metric: func(s Stats) float64 { return s.Throughput }, higher: true, unit: "/t"},
+	}
+	if len(runs) == 0 {
+		return nil
+	}
+	out := make([]reportWinner, 0, len(metrics))
+	for _, m := range metrics {
+		titles, best := bestBy(runs, m.metric, m.higher)
+		out = append(out, reportWinner{
+			Metric: m.name,
+			Titles: strings.Join(titles, ", "),
+			Value:  fmt.Sprintf("%.2f%s", best, m.unit),
+		})
+	}
+	return out
+}
+
+// writeHTML writes a single offline HTML page comparing runs: a summary of
+// the best algorithm per metric, then each run's Gantt chart and schedule
+// table side by side.
+func writeHTML(out Output, runs []Run) error {
+	data := struct {
+		Winners []reportWinner
+		Runs    []reportRun
+	}{
+		Winners: winners(runs),
+		Runs:    make([]reportRun, len(runs)),
+	}
+	for i, run := range runs {
+		var chart bytes.Buffer
+		writeGanttSVG(&chart, run, 0)
+		data.Runs[i] = reportRun{
+			Run: run,
+			// writeGanttSVG escapes everything it takes from the run.
+			Chart: template.HTML(chart.String()),
+		}
+	}
+	return reportTemplate.Execute(out.W, data)
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"f2":    func(v float64) string { return fmt.Sprintf("%.2f", v) },
+	"label": func(row ProcessResult) string { return row.label() },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Scheduler comparison</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+.runs { display: grid; grid-template-columns: repeat(auto-fit, minmax(820px, 1fr)); gap: 2em; }
+.run { border: 1px solid #ccc; border-radius: 4px; padding: 1em; }
+table { border-collapse: collapse; margin-top: 0.5em; }
+th, td { border: 1px solid #ccc; padding: 0.25em 0.75em; text-align: right; }
+th { background: #f4f4f4; }
+tfoot td { font-weight: bold; }
+.summary td:first-child, .summary th:first-child { text-align: left; }
+</style>
+</head>
+<body>
+<h1>Scheduler comparison</h1>
+<h2>Summary</h2>
+<table class="summary">
+<thead><tr><th>Metric</th><th>Best</th><th>Value</th></tr></thead>
+<tbody>
+{{- range .Winners}}
+<tr><td>{{.Metric}}</td><td>{{.Titles}}</td><td>{{.Value}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+<table class="summary">
+<thead><tr><th>Algorithm</th><th>Average wait</th><th>Average turnaround</th><th>Average response</th><th>Throughput</th></tr></thead>
+<tbody>
+{{- range .Runs}}
+<tr><td>{{.Title}}</td><td>{{f2 .Stats.AverageWait}}</td><td>{{f2 .Stats.AverageTurnaround}}</td><td>{{f2 .Stats.AverageResponse}}</td><td>{{f2 .Stats.Throughput}}/t</td></tr>
+{{- end}}
+</tbody>
+</table>
+<div class="runs">
+{{- range .Runs}}
+<section class="run">
+<h2>{{.Title}}</h2>
+{{- with .Parameters}}
+<p>{{range $k, $v := .}}{{$k}}={{$v}} {{end}}</p>
+{{- end}}
+{{.Chart}}
+<table>
+<thead><tr><th>ID</th><th>Priority</th><th>Burst</th><th>Arrival</th><th>Wait</th><th>Turnaround</th><th>Exit</th></tr></thead>
+<tbody>
+{{- range .Processes}}
+<tr><td>{{label .}}</td><td>{{.Priority}}</td><td>{{.BurstDuration}}</td><td>{{.ArrivalTime}}</td><td>{{.Wait}}</td><td>{{.Turnaround}}</td><td>{{.Completion}}</td></tr>
+{{- end}}
+</tbody>
+<tfoot><tr><td colspan="4"></td><td>{{f2 .Stats.AverageWait}}</td><td>{{f2 .Stats.AverageTurnaround}}</td><td>{{f2 .Stats.Throughput}}/t</td></tr></tfoot>
+</table>
+</section>
+{{- end}}
+</div>
+</body>
+</html>
+`))
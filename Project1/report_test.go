@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_winners(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		runs []Run
+		want []reportWinner
+	}{
+		{
+			name: "no runs",
+		},
+		{
+			name: "ties and higher throughput",
+			runs: []Run{
+				{Title: "A", Result: Result{Stats: Stats{AverageWait: 2, AverageTurnaround: 9, Throughput: 0.1}}},
+				{Title: "B", Result: Result{Stats: Stats{AverageWait: 1, AverageTurnaround: 9, Throughput: 0.25}}},
+				{Title: "C", Result: Result{Stats: Stats{AverageWait: 3, AverageTurnaround: 8, Throughput: 0.2}}},
+				{Title: "D", Result: Result{Stats: Stats{AverageWait: 1, AverageTurnaround: 10, Throughput: 0.1}}},
+			},
+			want: []reportWinner{
+				{Metric: "Average wait", Titles: "B, D", Value: "1.00"},
+				{Metric: "Average turnaround", Titles: "C", Value: "8.00"},
+				{Metric: "Throughput", Titles: "B", Value: "0.25/t"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := winners(tt.runs); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("winners() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	t.Parallel()
+	runs := []Run{
+		newRun(fcfs{}, exampleProcesses),
+		newRun(sjf{}, exampleProcesses),
+		newRun(rr{quantum: 3}, exampleProcesses),
+	}
+	runs[0].Title = "<FCFS>"
+	var w bytes.Buffer
+	if err := writeRuns("html", Output{W: &w}, runs); err != nil {
+		t.Fatalf("writeRuns() unexpected error: %v", err)
+	}
+	got := w.String()
+	if n := strings.Count(got, "<svg "); n != len(runs) {
+		t.Errorf("got %d charts, want %d", n, len(runs))
+	}
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		"&lt;FCFS&gt;",
+		"<tr><td>Average wait</td><td>Shortest-job-first (preemptive)</td><td>2.67</td></tr>",
+		"quantum=3",
+		"<tr><td>2</td><td>1</td><td>9</td><td>3</td><td>8</td><td>17</td><td>20</td></tr>",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("writeHTML() missing %q", want)
+		}
+	}
+	if strings.Contains(got, "<FCFS>") {
+		t.Error("writeHTML() did not escape the run title")
+	}
+	if strings.Contains(got, "http") {
+		t.Error("writeHTML() references external resources")
+	}
+}
+
+func TestWriteHTML_jobLabels(t *testing.T) {
+	t.Parallel()
+	processes, err := expandPeriodic([]Process{{ProcessID: 1, BurstDuration: 1, Period: 4}, {ProcessID: 2, BurstDuration: 2, Period: 8}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var w bytes.Buffer
+	if err := writeRuns("html", Output{W: &w}, []Run{newRun(rm{}, processes)}); err != nil {
+		t.Fatalf("writeRuns() unexpected error: %v", err)
+	}
+	if want := "<tr><td>1.2</td>"; !strings.Contains(w.String(), want) {
+		t.Errorf("writeHTML() missing %q", want)
+	}
+}
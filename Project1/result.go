@@ -0,0 +1,343 @@
+package main
+
+import "sort"
+
+type (
+	// ProcessResult is the timing of a single process in a schedule.
+	ProcessResult struct {
+		Process
+		Wait       int64 `json:"wait"`
+		Turnaround int64 `json:"turnaround"`
+		Completion int64 `json:"completion"`
+		// Response is the time from arrival until the process first ran.
+		Response int64 `json:"response"`
+		// Blocked is the time a process that does I/O spent waiting for and
+		// using the I/O device; Wait counts only time ready to run.
+		Blocked int64 `json:"blocked,omitempty"`
+		// Lateness is the completion time less the deadline, negative when
+		// the process finished early. It is only set for processes with a
+		// deadline.
+		Lateness int64 `json:"lateness,omitempty"`
+		Missed   bool  `json:"missed,omitempty"`
+	}
+
+	// Stats are the aggregate figures of a schedule.
+	Stats struct {
+		AverageWait       float64 `json:"average_wait"`
+		AverageTurnaround float64 `json:"average_turnaround"`
+		AverageResponse   float64 `json:"average_response"`
+		// Makespan is the time from the first arrival to the last
+		// completion, and Throughput the processes completed per tick of it.
+		Makespan   int64   `json:"makespan"`
+		Throughput float64 `json:"throughput"`
+		// MaxWait and P95Wait are the tail of the processes' waits.
+		MaxWait int64   `json:"max_wait"`
+		P95Wait float64 `json:"p95_wait"`
+		// AverageNormalisedTurnaround is the mean of each process's
+		// turnaround over its burst, how many times longer than its burst it
+		// took, and Fairness is Jain's fairness index of those: 1 when every
+		// process is slowed down alike, falling towards 1/n as a few are
+		// slowed down far more than the rest.
+		AverageNormalisedTurnaround float64 `json:"average_normalised_turnaround"`
+		Fairness                    float64 `json:"fairness"`
+		// Utilisation is the fraction of the time from the first arrival to
+		// the last completion the CPUs spent running processes, and Idle the
+		// CPU time they spent neither running processes nor switching.
+		Utilisation float64 `json:"utilisation"`
+		Idle        int64   `json:"idle"`
+		// ContextSwitches counts the times a CPU switched to a different
+		// process than the last it ran, whether or not switching has a cost.
+		ContextSwitches int `json:"context_switches"`
+	}
+
+	// DeadlineStats summarise how a schedule met the processes' deadlines.
+	DeadlineStats struct {
+		Processes int     `json:"processes"`
+		Missed    int     `json:"missed"`
+		MissRatio float64 `json:"miss_ratio"`
+		// TotalLateness and MaxLateness count only deadlines that were missed.
+		TotalLateness int64 `json:"total_lateness"`
+		MaxLateness   int64 `json:"max_lateness"`
+	}
+
+	// SwitchStats summarise the cost of context switching in a schedule.
+	SwitchStats struct {
+		Count int   `json:"count"`
+		Time  int64 `json:"time"`
+		// Utilisation is the fraction of the time from the first arrival to
+		// the last completion the CPU spent running processes.
+		Utilisation float64 `json:"utilisation"`
+	}
+
+	// ThroughputWindow is how many processes completed in one window of a
+	// schedule.
+	ThroughputWindow struct {
+		Start     int64 `json:"start"`
+		Stop      int64 `json:"stop"`
+		Completed int   `json:"completed"`
+		// Throughput is Completed per tick of the window.
+		Throughput float64 `json:"throughput"`
+	}
+
+	// CoreStats is how one CPU of the machine was used.
+	CoreStats struct {
+		CPU int `json:"cpu"`
+		// Speed is relative to a fast CPU.
+		Speed float64 `json:"speed"`
+		// Busy is the ticks the CPU ran processes, and Utilisation the
+		// fraction of the time from the first arrival to the last
+		// completion that is.
+		Busy        int64   `json:"busy"`
+		Utilisation float64 `json:"utilisation"`
+		// Energy is the energy the CPU used running processes, in ticks
+		// of a slow CPU. It is a float as a fast CPU that runs for long
+		// uses more than an int64 holds.
+		Energy float64 `json:"energy"`
+		// Completed is the number of processes that completed on the CPU.
+		Completed int `json:"completed"`
+	}
+
+	// Share compares the CPU a process received over its lifetime with its
+	// proportional share of the CPU.
+	Share struct {
+		PID     int64 `json:"pid"`
+		Tickets int64 `json:"tickets"`
+		// Expected is the fraction of the CPU its tickets entitled it to
+		// while it was runnable, and Received the fraction it got.
+		Expected float64 `json:"expected"`
+		Received float64 `json:"received"`
+	}
+
+	// Analysis is a fixed-priority schedulability analysis of a set of
+	// periodic tasks.
+	Analysis struct {
+		Tasks       []TaskAnalysis `json:"tasks"`
+		Utilisation float64        `json:"utilisation"`
+		// Bound is the Liu & Layland utilisation bound n(2^(1/n) - 1), under
+		// which the tasks are certainly schedulable.
+		Bound float64 `json:"bound"`
+		// Schedulable is set when every task's worst-case response time is
+		// within its deadline.
+		Schedulable bool `json:"schedulable"`
+	}
+
+	// TaskAnalysis is the analysis of one periodic task, in priority order.
+	TaskAnalysis struct {
+		PID         int64   `json:"pid"`
+		Period      int64   `json:"period"`
+		WCET        int64   `json:"wcet"`
+		Deadline    int64   `json:"deadline"`
+		Utilisation float64 `json:"utilisation"`
+		// Response is the worst-case response time from exact response-time
+		// analysis; when the task is unschedulable it is the first estimate
+		// past the deadline.
+		Response    int64 `json:"response"`
+		Schedulable bool  `json:"schedulable"`
+	}
+
+	// PriorityHistory is how the effective priority of a process changed
+	// as it aged while waiting.
+	PriorityHistory struct {
+		PID     int64            `json:"pid"`
+		Job     int64            `json:"job,omitempty"`
+		Changes []PriorityChange `json:"changes"`
+	}
+
+	// PriorityChange is an effective priority taking effect.
+	PriorityChange struct {
+		At       int64 `json:"at"`
+		Priority int64 `json:"priority"`
+	}
+
+	// Result is the outcome of running a Scheduler over a workload.
+	Result struct {
+		Gantt     []TimeSlice     `json:"gantt"`
+		Processes []ProcessResult `json:"processes"`
+		Stats     Stats           `json:"stats"`
+		// IO is the timeline of the I/O device, when processes do I/O.
+		IO []TimeSlice `json:"io,omitempty"`
+		// Deadlines is reported when any process has a deadline.
+		Deadlines *DeadlineStats `json:"deadlines,omitempty"`
+		// CPUs is the number of processors of a multiprocessor schedule, and
+		// Migrations the number of times a process moved between them.
+		CPUs       int `json:"cpus,omitempty"`
+		Migrations int `json:"migrations,omitempty"`
+		// Cores is reported for a machine of several or slow CPUs.
+		Cores []CoreStats `json:"cores,omitempty"`
+		// Switches is reported when context switches have a cost.
+		Switches *SwitchStats `json:"switches,omitempty"`
+		// Shares is reported by proportional-share schedulers.
+		Shares []Share `json:"shares,omitempty"`
+		// Analysis is the schedulability analysis of the periodic tasks,
+		// reported by rate monotonic scheduling.
+		Analysis *Analysis `json:"analysis,omitempty"`
+		// Priorities is reported by priority scheduling with aging.
+		Priorities []PriorityHistory `json:"priorities,omitempty"`
+		// Windows is the throughput over successive windows of the
+		// schedule, reported when asked for.
+		Windows []ThroughputWindow `json:"windows,omitempty"`
+	}
+)
+
+// newResult builds a Result from the per-process wait and completion times
+// computed by a scheduler.
+func newResult(processes []Process, gantt []TimeSlice, wait, completion []int64) Result {
+	var (
+		firstRun = make(map[[2]int64]int64, len(processes))
+		rows     = make([]ProcessResult, len(processes))
+		stats    Stats
+		waits    = make([]float64, len(processes))
+		// sum and squares total the normalised turnarounds for Jain's
+		// index, (Σx)² / nΣx².
+		sum, squares float64
+	)
+	for i := len(gantt) - 1; i >= 0; i-- {
+		if gantt[i].Switch {
+			continue
+		}
+		firstRun[[2]int64{gantt[i].PID, gantt[i].Job}] = gantt[i].Start
+	}
+	for i, p := range processes {
+		rows[i] = ProcessResult{
+			Process:    p,
+			Wait:       wait[i],
+			Turnaround: completion[i] - p.ArrivalTime,
+			Completion: completion[i],
+		}
+		if start, ok := firstRun[[2]int64{p.ProcessID, p.Job}]; ok && start > p.ArrivalTime {
+			rows[i].Response = start - p.ArrivalTime
+		}
+		if p.Deadline > 0 {
+			rows[i].Lateness = rows[i].Completion - p.Deadline
+			rows[i].Missed = rows[i].Lateness > 0
+		}
+		stats.AverageWait += float64(rows[i].Wait)
+		stats.AverageTurnaround += float64(rows[i].Turnaround)
+		stats.AverageResponse += float64(rows[i].Response)
+		if rows[i].Wait > stats.MaxWait {
+			stats.MaxWait = rows[i].Wait
+		}
+		waits[i] = float64(rows[i].Wait)
+		normalised := float64(rows[i].Turnaround) / float64(max64(p.BurstDuration, 1))
+		sum += normalised
+		squares += normalised * normalised
+	}
+	count := float64(len(processes))
+	stats.AverageWait /= count
+	stats.AverageTurnaround /= count
+	stats.AverageResponse /= count
+	if first, last := span(rows); last > first {
+		stats.Makespan = last - first
+		stats.Throughput = count / float64(stats.Makespan)
+	}
+	if len(waits) > 0 {
+		sort.Float64s(waits)
+		stats.P95Wait = percentile(waits, 95)
+	}
+	stats.AverageNormalisedTurnaround = sum / count
+	if squares > 0 {
+		stats.Fairness = sum * sum / (count * squares)
+	}
+
+	return Result{
+		Gantt:     gantt,
+		Processes: rows,
+		Stats:     stats,
+		Deadlines: deadlineStats(rows),
+		Switches:  switchStats(gantt, rows),
+	}
+}
+
+// switchStats totals the context switches in gantt, or returns nil if it has
+// none.
+func switchStats(gantt []TimeSlice, rows []ProcessResult) *SwitchStats {
+	var ss SwitchStats
+	for _, slice := range gantt {
+		if slice.Switch {
+			ss.Count++
+			ss.Time += slice.Stop - slice.Start
+		}
+	}
+	if ss.Count == 0 {
+		return nil
+	}
+	var busy int64
+	for _, slice := range gantt {
+		if !slice.Switch {
+			busy += slice.Stop - slice.Start
+		}
+	}
+	if first, last := span(rows); last > first {
+		ss.Utilisation = float64(busy) / float64(last-first)
+	}
+	return &ss
+}
+
+// span returns the first arrival and last completion of rows.
+func span(rows []ProcessResult) (first, last int64) {
+	if len(rows) == 0 {
+		return 0, 0
+	}
+	first, last = rows[0].ArrivalTime, rows[0].Completion
+	for _, row := range rows {
+		if row.ArrivalTime < first {
+			first = row.ArrivalTime
+		}
+		if row.Completion > last {
+			last = row.Completion
+		}
+	}
+	return first, last
+}
+
+// throughputWindows counts the completions of rows in successive windows of
+// width ticks from the first arrival, the last window ending at the last
+// completion.
+func throughputWindows(rows []ProcessResult, width int64) []ThroughputWindow {
+	first, last := span(rows)
+	if last <= first {
+		return nil
+	}
+	windows := make([]ThroughputWindow, ceilDiv(last-first, width))
+	for i := range windows {
+		windows[i].Start = first + int64(i)*width
+		windows[i].Stop = min64(windows[i].Start+width, last)
+	}
+	for _, row := range rows {
+		// A completion at the end of a window counts towards it.
+		i := ceilDiv(row.Completion-first, width) - 1
+		if i < 0 {
+			i = 0
+		}
+		windows[i].Completed++
+	}
+	for i := range windows {
+		windows[i].Throughput = float64(windows[i].Completed) / float64(windows[i].Stop-windows[i].Start)
+	}
+	return windows
+}
+
+// deadlineStats summarises the rows with deadlines, or returns nil if none
+// have one.
+func deadlineStats(rows []ProcessResult) *DeadlineStats {
+	var ds DeadlineStats
+	for _, row := range rows {
+		if row.Deadline <= 0 {
+			continue
+		}
+		ds.Processes++
+		if !row.Missed {
+			continue
+		}
+		ds.Missed++
+		ds.TotalLateness += row.Lateness
+		if row.Lateness > ds.MaxLateness {
+			ds.MaxLateness = row.Lateness
+		}
+	}
+	if ds.Processes == 0 {
+		return nil
+	}
+	ds.MissRatio = float64(ds.Missed) / float64(ds.Processes)
+	return &ds
+}
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+// exampleProcesses mirrors example_processes.csv.
+var exampleProcesses = []Process{
+	{ProcessID: 1, BurstDuration: 5, ArrivalTime: 0, Priority: 2},
+	{ProcessID: 2, BurstDuration: 9, ArrivalTime: 3, Priority: 1},
+	{ProcessID: 3, BurstDuration: 6, ArrivalTime: 6, Priority: 3},
+}
+
+func TestSchedule_results(t *testing.T) {
+	t.Parallel()
+	type timing struct {
+		wait, turnaround, completion, response int64
+	}
+	tests := []struct {
+		name      string
+		scheduler Scheduler
+		wantGantt []TimeSlice
+		want      []timing
+		wantStats Stats
+	}{
+		{
+			name:      "fcfs",
+			scheduler: fcfs{},
+			wantGantt: []TimeSlice{{PID: 1, Start: 0, Stop: 5}, {PID: 2, Start: 5, Stop: 14}, {PID: 3, Start: 14, Stop: 20}},
+			want:      []timing{{0, 5, 5, 0}, {2, 11, 14, 2}, {8, 14, 20, 8}},
+			wantStats: Stats{
+				AverageWait: 10.0 / 3, AverageTurnaround: 30.0 / 3, AverageResponse: 10.0 / 3, Throughput: 3.0 / 20,
+				MaxWait: 8, P95Wait: 7.4, AverageNormalisedTurnaround: 41.0 / 27, Fairness: 0.8714359772, Utilisation: 1, ContextSwitches: 2,
+			},
+		},
+		{
+			name:      "sjf",
+			scheduler: sjf{},
+			wantGantt: []TimeSlice{{PID: 1, Start: 0, Stop: 5}, {PID: 2, Start: 5, Stop: 6}, {PID: 3, Start: 6, Stop: 12}, {PID: 2, Start: 12, Stop: 20}},
+			want:      []timing{{0, 5, 5, 0}, {8, 17, 20, 2}, {0, 6, 12, 0}},
+			wantStats: Stats{
+				AverageWait: 8.0 / 3, AverageTurnaround: 28.0 / 3, AverageResponse: 2.0 / 3, Throughput: 3.0 / 20,
+				MaxWait: 8, P95Wait: 7.2, AverageNormalisedTurnaround: 35.0 / 27, Fairness: 0.9053954176, Utilisation: 1, ContextSwitches: 3,
+			},
+		},
+		{
+			name:      "priority",
+			scheduler: priority{},
+			wantGantt: []TimeSlice{{PID: 1, Start: 0, Stop: 3}, {PID: 2, Start: 3, Stop: 12}, {PID: 1, Start: 12, Stop: 14}, {PID: 3, Start: 14, Stop: 20}},
+			want:      []timing{{9, 14, 14, 0}, {0, 9, 12, 0}, {8, 14, 20, 8}},
+			wantStats: Stats{
+				AverageWait: 17.0 / 3, AverageTurnaround: 37.0 / 3, AverageResponse: 8.0 / 3, Throughput: 3.0 / 20,
+				MaxWait: 9, P95Wait: 8.9, AverageNormalisedTurnaround: 92.0 / 45, Fairness: 0.8778261771, Utilisation: 1, ContextSwitches: 3,
+			},
+		},
+		{
+			name:      "sjf-np",
+			scheduler: sjfNP{},
+			wantGantt: []TimeSlice{{PID: 1, Start: 0, Stop: 5}, {PID: 2, Start: 5, Stop: 14}, {PID: 3, Start: 14, Stop: 20}},
+			want:      []timing{{0, 5, 5, 0}, {2, 11, 14, 2}, {8, 14, 20, 8}},
+			wantStats: Stats{
+				AverageWait: 10.0 / 3, AverageTurnaround: 30.0 / 3, AverageResponse: 10.0 / 3, Throughput: 3.0 / 20,
+				MaxWait: 8, P95Wait: 7.4, AverageNormalisedTurnaround: 41.0 / 27, Fairness: 0.8714359772, Utilisation: 1, ContextSwitches: 2,
+			},
+		},
+		{
+			name:      "priority-np",
+			scheduler: priorityNP{},
+			wantGantt: []TimeSlice{{PID: 1, Start: 0, Stop: 5}, {PID: 2, Start: 5, Stop: 14}, {PID: 3, Start: 14, Stop: 20}},
+			want:      []timing{{0, 5, 5, 0}, {2, 11, 14, 2}, {8, 14, 20, 8}},
+			wantStats: Stats{
+				AverageWait: 10.0 / 3, AverageTurnaround: 30.0 / 3, AverageResponse: 10.0 / 3, Throughput: 3.0 / 20,
+				MaxWait: 8, P95Wait: 7.4, AverageNormalisedTurnaround: 41.0 / 27, Fairness: 0.8714359772, Utilisation: 1, ContextSwitches: 2,
+			},
+		},
+		{
+			name:      "rr",
+			scheduler: rr{quantum: 5},
+			wantGantt: []TimeSlice{{PID: 1, Start: 0, Stop: 5}, {PID: 2, Start: 5, Stop: 10}, {PID: 3, Start: 10, Stop: 15}, {PID: 2, Start: 15, Stop: 19}, {PID: 3, Start: 19, Stop: 20}},
+			want:      []timing{{0, 5, 5, 0}, {7, 16, 19, 2}, {8, 14, 20, 4}},
+			wantStats: Stats{
+				AverageWait: 15.0 / 3, AverageTurnaround: 35.0 / 3, AverageResponse: 6.0 / 3, Throughput: 3.0 / 20,
+				MaxWait: 8, P95Wait: 7.9, AverageNormalisedTurnaround: 46.0 / 27, Fairness: 0.9065981148, Utilisation: 1, ContextSwitches: 4,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := tt.scheduler.Schedule(exampleProcesses)
+			if !reflect.DeepEqual(got.Gantt, tt.wantGantt) {
+				t.Errorf("Gantt = %v, want %v", got.Gantt, tt.wantGantt)
+			}
+			for i, row := range got.Processes {
+				if !reflect.DeepEqual(row.Process, exampleProcesses[i]) {
+					t.Errorf("row %d process = %v, want %v", i, row.Process, exampleProcesses[i])
+				}
+				gotTiming := timing{row.Wait, row.Turnaround, row.Completion, row.Response}
+				if gotTiming != tt.want[i] {
+					t.Errorf("row %d timing = %+v, want %+v", i, gotTiming, tt.want[i])
+				}
+			}
+			if !statsEqual(got.Stats, tt.wantStats) {
+				t.Errorf("Stats = %+v, want %+v", got.Stats, tt.wantStats)
+			}
+		})
+	}
+}
+
+// statsEqual reports whether a and b are equal, allowing for rounding in
+// the floating point figures.
+func statsEqual(a, b Stats) bool {
+	near := func(x, y float64) bool { return math.Abs(x-y) < 1e-9 }
+	return near(a.AverageWait, b.AverageWait) &&
+		near(a.AverageTurnaround, b.AverageTurnaround) &&
+		near(a.AverageResponse, b.AverageResponse) &&
+		near(a.Throughput, b.Throughput) &&
+		a.MaxWait == b.MaxWait &&
+		near(a.P95Wait, b.P95Wait) &&
+		near(a.AverageNormalisedTurnaround, b.AverageNormalisedTurnaround) &&
+		near(a.Fairness, b.Fairness) &&
+		near(a.Utilisation, b.Utilisation) &&
+		a.Idle == b.Idle &&
+		a.ContextSwitches == b.ContextSwitches
+}
+
+func TestNonPreemptive_Schedule(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 8, Priority: 3},
+		{ProcessID: 2, BurstDuration: 4, ArrivalTime: 1, Priority: 1},
+		{ProcessID: 3, BurstDuration: 2, ArrivalTime: 2, Priority: 2},
+		{ProcessID: 4, BurstDuration: 1, ArrivalTime: 3, Priority: 1},
+	}
+	tests := []struct {
+		name      string
+		scheduler Scheduler
+		wantGantt []TimeSlice
+	}{
+		{
+			name:      "sjf-np",
+			scheduler: sjfNP{},
+			wantGantt: []TimeSlice{{PID: 1, Start: 0, Stop: 8}, {PID: 4, Start: 8, Stop: 9}, {PID: 3, Start: 9, Stop: 11}, {PID: 2, Start: 11, Stop: 15}},
+		},
+		{
+			name:      "priority-np",
+			scheduler: priorityNP{},
+			wantGantt: []TimeSlice{{PID: 1, Start: 0, Stop: 8}, {PID: 4, Start: 8, Stop: 9}, {PID: 2, Start: 9, Stop: 13}, {PID: 3, Start: 13, Stop: 15}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.scheduler.Schedule(processes); !reflect.DeepEqual(got.Gantt, tt.wantGantt) {
+				t.Errorf("Gantt = %v, want %v", got.Gantt, tt.wantGantt)
+			}
+		})
+	}
+}
+
+func TestSchedule_metrics(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		scheduler Scheduler
+		processes []Process
+		want      Stats
+	}{
+		{
+			name:      "idle gap",
+			scheduler: fcfs{},
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 2},
+				{ProcessID: 2, BurstDuration: 2, ArrivalTime: 5},
+			},
+			want: Stats{Utilisation: 4.0 / 7, Idle: 3, ContextSwitches: 1, Fairness: 1, AverageNormalisedTurnaround: 1, Makespan: 7, Throughput: 2.0 / 7},
+		},
+		{
+			name:      "two CPUs",
+			scheduler: fcfs{machine: machine{cpus: 2}},
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 4},
+				{ProcessID: 2, BurstDuration: 2},
+			},
+			want: Stats{Utilisation: 6.0 / 8, Idle: 2, Fairness: 1, AverageNormalisedTurnaround: 1, Makespan: 4, Throughput: 2.0 / 4},
+		},
+		{
+			name:      "switching takes time but is not idle",
+			scheduler: rr{machine: machine{switchCost: 1}, quantum: 1},
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 2},
+				{ProcessID: 2, BurstDuration: 2},
+			},
+			want: Stats{Utilisation: 4.0 / 7, ContextSwitches: 3, Fairness: 36.0 / 37, AverageNormalisedTurnaround: 3, Makespan: 7, Throughput: 2.0 / 7},
+		},
+		{
+			name:      "last row completes first",
+			scheduler: sjf{},
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 10},
+				{ProcessID: 2, BurstDuration: 1},
+			},
+			want: Stats{Utilisation: 1, ContextSwitches: 1, Fairness: 4.41 / 4.42, AverageNormalisedTurnaround: 1.05, Makespan: 11, Throughput: 2.0 / 11},
+		},
+		{
+			name:      "late first arrival",
+			scheduler: fcfs{},
+			processes: []Process{{ProcessID: 1, BurstDuration: 5, ArrivalTime: 10}},
+			want:      Stats{Utilisation: 1, Fairness: 1, AverageNormalisedTurnaround: 1, Makespan: 5, Throughput: 0.2},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := tt.scheduler.Schedule(tt.processes)
+			if math.Abs(got.Stats.Utilisation-tt.want.Utilisation) > 1e-9 {
+				t.Errorf("Utilisation = %v, want %v", got.Stats.Utilisation, tt.want.Utilisation)
+			}
+			if got.Stats.Makespan != tt.want.Makespan {
+				t.Errorf("Makespan = %v, want %v", got.Stats.Makespan, tt.want.Makespan)
+			}
+			if math.Abs(got.Stats.Throughput-tt.want.Throughput) > 1e-9 {
+				t.Errorf("Throughput = %v, want %v", got.Stats.Throughput, tt.want.Throughput)
+			}
+			if got.Stats.Idle != tt.want.Idle {
+				t.Errorf("Idle = %v, want %v", got.Stats.Idle, tt.want.Idle)
+			}
+			if got.Stats.ContextSwitches != tt.want.ContextSwitches {
+				t.Errorf("ContextSwitches = %v, want %v", got.Stats.ContextSwitches, tt.want.ContextSwitches)
+			}
+			if got.Switches != nil && got.Switches.Count != got.Stats.ContextSwitches {
+				t.Errorf("Switches.Count = %v, ContextSwitches %v", got.Switches.Count, got.Stats.ContextSwitches)
+			}
+			if math.Abs(got.Stats.Fairness-tt.want.Fairness) > 1e-9 {
+				t.Errorf("Fairness = %v, want %v", got.Stats.Fairness, tt.want.Fairness)
+			}
+			if math.Abs(got.Stats.AverageNormalisedTurnaround-tt.want.AverageNormalisedTurnaround) > 1e-9 {
+				t.Errorf("AverageNormalisedTurnaround = %v, want %v", got.Stats.AverageNormalisedTurnaround, tt.want.AverageNormalisedTurnaround)
+			}
+		})
+	}
+}
+
+func Test_throughputWindows(t *testing.T) {
+	t.Parallel()
+	rows := func(arrival int64, completions ...int64) []ProcessResult {
+		rows := make([]ProcessResult, len(completions))
+		for i, c := range completions {
+			rows[i] = ProcessResult{Process: Process{ArrivalTime: arrival}, Completion: c}
+		}
+		return rows
+	}
+	tests := []struct {
+		name  string
+		rows  []ProcessResult
+		width int64
+		want  []ThroughputWindow
+	}{
+		{
+			name:  "completions on a window's end count towards it",
+			rows:  rows(0, 5, 14, 20),
+			width: 10,
+			want: []ThroughputWindow{
+				{Start: 0, Stop: 10, Completed: 1, Throughput: 0.1},
+				{Start: 10, Stop: 20, Completed: 2, Throughput: 0.2},
+			},
+		},
+		{
+			name:  "windows start at the first arrival and the last is cut short",
+			rows:  rows(3, 8, 4, 9),
+			width: 4,
+			want: []ThroughputWindow{
+				{Start: 3, Stop: 7, Completed: 1, Throughput: 0.25},
+				{Start: 7, Stop: 9, Completed: 2, Throughput: 1},
+			},
+		},
+		{
+			name:  "empty schedule",
+			width: 4,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := throughputWindows(tt.rows, tt.width); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("throughputWindows() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSchedule_ganttMatchesBursts checks every algorithm runs each process
+// for exactly its burst, so expectations cannot encode a schedule that runs
+// a process too long or too short.
+func TestSchedule_ganttMatchesBursts(t *testing.T) {
+	t.Parallel()
+	for _, name := range Algorithms() {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, err := NewScheduler(name, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ran := make(map[int64]int64)
+			for _, slice := range s.Schedule(exampleProcesses).Gantt {
+				if !slice.Switch {
+					ran[slice.PID] += slice.Stop - slice.Start
+				}
+			}
+			for _, p := range exampleProcesses {
+				if ran[p.ProcessID] != p.BurstDuration {
+					t.Errorf("process %d ran %d ticks, want its burst of %d", p.ProcessID, ran[p.ProcessID], p.BurstDuration)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"math"
+	"sort"
+)
+
+func init() {
+	Register("rm", func(params Parameters) (Scheduler, error) {
+		m, err := newMachine(params)
+		return rm{m}, err
+	})
+}
+
+// rm is preemptive rate monotonic scheduling: jobs of the periodic task with
+// the shortest period run first. Processes that are not periodic run in the
+// background in order of arrival.
+type rm struct {
+	machine
+}
+
+func (rm) Name() string             { return "rm" }
+func (rm) Title() string            { return "Rate monotonic" }
+func (s rm) Parameters() Parameters { return s.parameters(Parameters{}) }
+
+func (s rm) Schedule(processes []Process) Result {
+	res := s.simulate(processes, orderBy(higherRate, true))
+	if tasks := periodicTasks(processes); len(tasks) > 0 {
+		sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].Period < tasks[j].Period })
+		res.Analysis = analyse(tasks)
+	}
+	return res
+}
+
+// rate returns the period of t, or the end of time if it is not periodic.
+func rate(t *task) int64 {
+	if t.Period <= 0 {
+		return math.MaxInt64
+	}
+	return t.Period
+}
+
+// higherRate orders tasks by period, then arrival, then workload order.
+func higherRate(a, b *task) bool {
+	if rate(a) != rate(b) {
+		return rate(a) < rate(b)
+	}
+	return byArrival(a, b)
+}
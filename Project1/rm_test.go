@@ -0,0 +1,41 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRM_Schedule(t *testing.T) {
+	t.Parallel()
+	processes, err := expandPeriodic([]Process{
+		{ProcessID: 1, BurstDuration: 1, Period: 4},
+		{ProcessID: 2, BurstDuration: 2, Period: 6},
+		{ProcessID: 3, BurstDuration: 3, Period: 12},
+		{ProcessID: 4, BurstDuration: 1, ArrivalTime: 2},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := rm{}.Schedule(processes)
+
+	wantGantt := []TimeSlice{
+		{PID: 1, Job: 1, Start: 0, Stop: 1},
+		{PID: 2, Job: 1, Start: 1, Stop: 3},
+		{PID: 3, Job: 1, Start: 3, Stop: 4},
+		{PID: 1, Job: 2, Start: 4, Stop: 5},
+		{PID: 3, Job: 1, Start: 5, Stop: 6},
+		{PID: 2, Job: 2, Start: 6, Stop: 8},
+		{PID: 1, Job: 3, Start: 8, Stop: 9},
+		{PID: 3, Job: 1, Start: 9, Stop: 10},
+		{PID: 4, Start: 10, Stop: 11},
+	}
+	if !reflect.DeepEqual(got.Gantt, wantGantt) {
+		t.Errorf("Gantt = %v, want %v", got.Gantt, wantGantt)
+	}
+	if got.Analysis == nil || !got.Analysis.Schedulable || len(got.Analysis.Tasks) != 3 {
+		t.Errorf("Analysis = %+v, want 3 schedulable tasks", got.Analysis)
+	}
+	if got.Deadlines == nil || got.Deadlines.Missed != 0 {
+		t.Errorf("Deadlines = %+v, want none missed", got.Deadlines)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"io"
+)
+
+func init() {
+	Register("rr", newRR)
+}
+
+// rr cycles through the arrived processes, running each for at most one
+// time quantum per turn.
+type rr struct {
+	machine
+	quantum int64
+}
+
+func newRR(params Parameters) (Scheduler, error) {
+	quantum, err := params.positive("quantum", 1)
+	if err != nil {
+		return nil, err
+	}
+	m, err := newMachine(params)
+	return rr{machine: m, quantum: quantum}, err
+}
+
+func (rr) Name() string             { return "rr" }
+func (rr) Title() string            { return "Round-robin" }
+func (s rr) Parameters() Parameters { return s.parameters(Parameters{"quantum": s.quantum}) }
+
+func (s rr) Schedule(processes []Process) Result {
+	return s.simulate(processes, func() policy { return &rrPolicy{quantum: s.quantum} })
+}
+
+// rrPolicy is the simulation state of an rr.
+type rrPolicy struct {
+	quantum int64
+	ready   fifo
+}
+
+func (p *rrPolicy) push(t *task, _ int64)    { p.ready.push(t) }
+func (p *rrPolicy) pop(int64) (*task, int64) { return p.ready.pop(), p.quantum }
+func (p *rrPolicy) empty() bool              { return len(p.ready) == 0 }
+func (p *rrPolicy) ran(*task, int64, bool)   {}
+
+// Round-round (preemptive) and report average turnaround time, average waiting time, and average throughput.
+func RRSchedule(w io.Writer, title string, processes []Process) {
+	outputResult(w, title, rr{quantum: 1}.Schedule(processes))
+}
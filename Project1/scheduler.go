@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var (
+	ErrUnknownScheduler = errors.New("unknown scheduler")
+	ErrInvalidParameter = errors.New("invalid scheduler parameter")
+)
+
+type (
+	// Parameters are the named tunables of a scheduling algorithm, e.g. the
+	// round-robin time quantum.
+	Parameters map[string]int64
+
+	// Scheduler is a CPU scheduling policy that can be run over a workload.
+	Scheduler interface {
+		// Name is the key the scheduler is registered under, e.g. "rr".
+		Name() string
+		// Title is the human readable name used in chart titles.
+		Title() string
+		// Parameters returns the tunables the scheduler was built with.
+		Parameters() Parameters
+		// Schedule runs the policy over processes.
+		Schedule(processes []Process) Result
+	}
+
+	// Factory builds a Scheduler from the given parameters. Missing
+	// parameters take the algorithm's defaults.
+	Factory func(params Parameters) (Scheduler, error)
+)
+
+var registry = make(map[string]Factory)
+
+// Register makes a scheduling algorithm available by name. It is meant to be
+// called from init and panics if the name is already taken.
+func Register(name string, factory Factory) {
+	if factory == nil {
+		panic("scheduler: Register factory is nil")
+	}
+	if _, dup := registry[name]; dup {
+		panic("scheduler: Register called twice for " + name)
+	}
+	registry[name] = factory
+}
+
+// NewScheduler builds the registered algorithm called name.
+func NewScheduler(name string, params Parameters) (Scheduler, error) {
+	factory, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownScheduler, name)
+	}
+	return factory(params)
+}
+
+// Algorithms returns the sorted names of all registered algorithms.
+func Algorithms() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get returns the value of the parameter key, or def if it is unset.
+func (p Parameters) Get(key string, def int64) int64 {
+	if v, ok := p[key]; ok {
+		return v
+	}
+	return def
+}
+
+// positive returns the parameter key, or def if it is unset, and errors if
+// the value is not greater than zero.
+func (p Parameters) positive(key string, def int64) (int64, error) {
+	v := p.Get(key, def)
+	if v <= 0 {
+		return 0, fmt.Errorf("%w: %s must be positive, got %d", ErrInvalidParameter, key, v)
+	}
+	return v, nil
+}
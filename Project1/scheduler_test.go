@@ -0,0 +1,137 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNewScheduler(t *testing.T) {
+	t.Parallel()
+	type args struct {
+		name   string
+		params Parameters
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantTitle  string
+		wantParams Parameters
+		wantErr    error
+	}{
+		{
+			name:       "fcfs",
+			args:       args{name: "fcfs"},
+			wantTitle:  "First-come, first-serve",
+			wantParams: Parameters{},
+		},
+		{
+			name:       "sjf",
+			args:       args{name: "sjf"},
+			wantTitle:  "Shortest-job-first (preemptive)",
+			wantParams: Parameters{},
+		},
+		{
+			name:       "sjf-np",
+			args:       args{name: "sjf-np"},
+			wantTitle:  "Shortest-job-first (non-preemptive)",
+			wantParams: Parameters{},
+		},
+		{
+			name:       "priority-np",
+			args:       args{name: "priority-np"},
+			wantTitle:  "Priority (non-preemptive)",
+			wantParams: Parameters{"aging": 0, "aging_interval": 10},
+		},
+		{
+			name:       "priority aging",
+			args:       args{name: "priority", params: Parameters{"aging": 1, "aging_interval": 5}},
+			wantTitle:  "Priority (preemptive)",
+			wantParams: Parameters{"aging": 1, "aging_interval": 5},
+		},
+		{
+			name:    "priority negative aging",
+			args:    args{name: "priority", params: Parameters{"aging": -1}},
+			wantErr: ErrInvalidParameter,
+		},
+		{
+			name:       "rr default quantum",
+			args:       args{name: "rr"},
+			wantTitle:  "Round-robin",
+			wantParams: Parameters{"quantum": 1},
+		},
+		{
+			name:       "rr custom quantum",
+			args:       args{name: "rr", params: Parameters{"quantum": 4}},
+			wantTitle:  "Round-robin",
+			wantParams: Parameters{"quantum": 4},
+		},
+		{
+			name:    "rr bad quantum",
+			args:    args{name: "rr", params: Parameters{"quantum": 0}},
+			wantErr: ErrInvalidParameter,
+		},
+		{
+			name:       "big.LITTLE machine",
+			args:       args{name: "fcfs", params: Parameters{"cpus": 4, "little": 2, "energy": 1}},
+			wantTitle:  "First-come, first-serve",
+			wantParams: Parameters{"cpus": 4, "little": 2, "slowdown": 2, "energy": 1},
+		},
+		{
+			name:    "more little CPUs than CPUs",
+			args:    args{name: "fcfs", params: Parameters{"cpus": 2, "little": 3}},
+			wantErr: ErrInvalidParameter,
+		},
+		{
+			name:    "unknown",
+			args:    args{name: "nope"},
+			wantErr: ErrUnknownScheduler,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := NewScheduler(tt.args.name, tt.args.params)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewScheduler() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Name() != tt.args.name {
+				t.Errorf("Name() = %v, want %v", got.Name(), tt.args.name)
+			}
+			if got.Title() != tt.wantTitle {
+				t.Errorf("Title() = %v, want %v", got.Title(), tt.wantTitle)
+			}
+			if !reflect.DeepEqual(got.Parameters(), tt.wantParams) {
+				t.Errorf("Parameters() = %v, want %v", got.Parameters(), tt.wantParams)
+			}
+		})
+	}
+}
+
+func TestAlgorithms(t *testing.T) {
+	t.Parallel()
+	got := Algorithms()
+	for _, name := range defaultAlgorithms {
+		found := false
+		for _, g := range got {
+			found = found || g == name
+		}
+		if !found {
+			t.Errorf("Algorithms() = %v, missing %v", got, name)
+		}
+	}
+}
+
+func TestRegisterDuplicate(t *testing.T) {
+	t.Parallel()
+	defer func() {
+		if recover() == nil {
+			t.Error("Register() did not panic on duplicate name")
+		}
+	}()
+	Register("fcfs", func(Parameters) (Scheduler, error) { return fcfs{}, nil })
+}
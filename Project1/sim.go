@@ -0,0 +1,644 @@
+package main
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+type (
+	// task is a process as it moves through a simulation.
+	task struct {
+		Process
+		// index is the position of the process in the workload.
+		index int
+		// remaining is the CPU burst left to run, in units of 1/scale of a
+		// tick of burst on the machine.
+		remaining  int64
+		completion int64
+		// cpuTime is the ticks the task has spent running.
+		cpuTime int64
+		// phase is the index of the current burst of a task that does I/O,
+		// and blocked the ticks it has spent blocked on the device, the
+		// latest time since blockedAt.
+		phase     int
+		blocked   int64
+		blockedAt int64
+		// cpu is the CPU the task last ran on, -1 before it first runs.
+		cpu int
+	}
+
+	// policy decides which ready task runs next in a simulation.
+	policy interface {
+		// push makes t ready to run at now, on arrival or after leaving the
+		// CPU without completing.
+		push(t *task, now int64)
+		// pop removes the task to run next and returns the longest it may run
+		// before the policy chooses again, 0 meaning until it completes.
+		pop(now int64) (t *task, slice int64)
+		// empty reports whether no task is ready.
+		empty() bool
+		// ran charges t with d ticks of CPU time. expired is set when the
+		// slice pop gave it has run out before it completed.
+		ran(t *task, d int64, expired bool)
+	}
+
+	// preempter is a policy that may take the CPU from the running task
+	// when another task becomes ready.
+	preempter interface {
+		preempts(running *task, now int64) bool
+	}
+
+	// timer is a policy that needs to act at times of its own choosing, such
+	// as periodic priority boosts.
+	timer interface {
+		// nextTimer returns the first time after now the policy wants to act.
+		nextTimer(now int64) (int64, bool)
+		// fire lets the policy act at now; running is the task on the CPU, if
+		// any.
+		fire(running *task, now int64)
+	}
+)
+
+// simulate runs processes on the CPUs of m, advancing the clock straight to
+// the next arrival, slice expiry, completion, context switch, end of I/O or
+// timer rather than tick by tick, so the time it takes grows with the number
+// of events rather than the length of the schedule. Each run queue is
+// scheduled by a policy from newPolicy.
+//
+// The ends of switches, slices, bursts and I/O wait on a priority queue of
+// events. Arrivals, all known in advance, are taken in order from the sorted
+// workload, and policies' timers are asked for as they may move.
+//
+// Switching a CPU to a different process than the last one it ran takes the
+// switch cost, during which nothing runs on it. With a run queue per CPU,
+// arrivals go to the least loaded CPU they may run on and a CPU that runs out
+// of work takes the next task of the busiest one. Slow CPUs take longer to get
+// through a burst; energy-aware placement prefers them while they are idle and
+// otherwise the CPU that would finish the task soonest.
+//
+// A process with I/O bursts blocks at the end of each CPU burst and queues
+// first come, first served for the I/O device, becoming ready again when its
+// I/O is done.
+func simulate(processes []Process, newPolicy func() policy, m machine) Result {
+	var (
+		tasks   = make([]task, len(processes))
+		arrival = make([]*task, len(processes))
+		gantt   = make([]TimeSlice, 0)
+		cpus    = make([]cpu, m.cpus)
+		queues  []*runQueue
+		order   []*cpu
+		all     = ^uint64(0) >> (maxCPUs - len(cpus))
+
+		// device is the queue of tasks blocked for the I/O device, and
+		// doing the one using it until ioEnd.
+		device fifo
+		doing  *task
+		ioEnd  int64
+		io     = make([]TimeSlice, 0)
+		events eventQueue
+
+		now                        int64
+		next, done, migr, switches int
+		switchTime                 int64
+	)
+	if len(cpus) == 0 {
+		cpus, all = make([]cpu, 1), 1
+	}
+	for i := range processes {
+		tasks[i] = task{Process: processes[i], index: i, remaining: processes[i].BurstDuration * m.scale(), cpu: -1}
+		if len(processes[i].Bursts) > 0 {
+			tasks[i].remaining = processes[i].Bursts[0].Duration * m.scale()
+		}
+		arrival[i] = &tasks[i]
+	}
+	sort.SliceStable(arrival, func(i, j int) bool { return arrival[i].ArrivalTime < arrival[j].ArrivalTime })
+	for i := range cpus {
+		cpus[i].id, cpus[i].speed = i, m.speed(i)
+		order = append(order, &cpus[i])
+		if i == 0 || m.perCore {
+			q := &runQueue{policy: newPolicy()}
+			q.pre, _ = q.policy.(preempter)
+			q.tm, _ = q.policy.(timer)
+			queues = append(queues, q)
+		}
+		cpus[i].queue = queues[len(queues)-1]
+	}
+
+	// Idle CPUs take work in order, which for energy-aware placement is the
+	// slowest first, as a CPU's energy per tick of burst grows with its speed.
+	if m.energy {
+		sort.SliceStable(order, func(i, j int) bool { return order[i].speed < order[j].speed })
+	}
+
+	// allowed reports whether t may run on c. Affinity to no CPU of the
+	// machine is ignored.
+	allowed := func(t *task, c *cpu) bool {
+		mask := t.Affinity & all
+		return mask == 0 || mask&(1<<c.id) != 0
+	}
+
+	// place chooses the run queue of an arriving task when every CPU has
+	// its own.
+	place := func(t *task) (best *cpu) {
+		if !m.energy {
+			load := math.MaxInt
+			for i := range cpus {
+				if l := cpus[i].load(); allowed(t, &cpus[i]) && l < load {
+					best, load = &cpus[i], l
+				}
+			}
+			return best
+		}
+		var finish int64 = math.MaxInt64
+		for _, c := range order {
+			if !allowed(t, c) {
+				continue
+			}
+			if c.load() == 0 {
+				return c
+			}
+			if f := ceilDiv(c.work()+t.remaining, c.speed); f < finish {
+				best, finish = c, f
+			}
+		}
+		return best
+	}
+
+	// ready queues t to run.
+	ready := func(t *task) {
+		c := &cpus[0]
+		if m.perCore {
+			c = place(t)
+		}
+		c.queue.add(t, now)
+	}
+
+	// startIO gives the device to the next blocked task if it is free.
+	startIO := func() {
+		if doing != nil || len(device) == 0 {
+			return
+		}
+		doing = device.pop()
+		ioEnd = now + doing.Bursts[doing.phase].Duration
+		heap.Push(&events, event{at: ioEnd})
+		io = append(io, TimeSlice{PID: doing.ProcessID, Job: doing.Job, Start: now, Stop: ioEnd})
+	}
+
+	// block queues t for the device.
+	block := func(t *task) {
+		t.blockedAt = now
+		device.push(t)
+		startIO()
+	}
+
+	// advance moves t on to its next burst, or completes it at now if it has
+	// none.
+	advance := func(t *task) {
+		if t.phase++; t.phase >= len(t.Bursts) {
+			t.completion = now
+			done++
+			return
+		}
+		if b := t.Bursts[t.phase]; b.IO {
+			block(t)
+		} else {
+			t.remaining = b.Duration * m.scale()
+			ready(t)
+		}
+	}
+
+	// arrive queues the arrivals up to now.
+	arrive := func() {
+		for ; next < len(arrival) && arrival[next].ArrivalTime <= now; next++ {
+			if t := arrival[next]; len(t.Bursts) > 0 && t.Bursts[0].IO {
+				block(t)
+			} else {
+				ready(t)
+			}
+		}
+	}
+
+	// take removes the next task in q that c may run, putting back the ones
+	// it skips.
+	take := func(c *cpu, q *runQueue) (t *task, slice int64) {
+		var skipped []*task
+		for t == nil && !q.empty() {
+			t, slice = q.pop(now)
+			q.queued--
+			q.work -= t.remaining
+			if !allowed(t, c) {
+				skipped = append(skipped, t)
+				t = nil
+			}
+		}
+		for _, s := range skipped {
+			q.add(s, now)
+		}
+		return t, slice
+	}
+
+	// run starts c running its task once any context switch is done.
+	run := func(c *cpu) {
+		c.switching = false
+		c.runStart, c.sliceEnd = now, math.MaxInt64
+		// A slice too long to end in time is as good as none.
+		if c.slice > 0 && c.slice <= math.MaxInt64-now {
+			c.sliceEnd = now + c.slice
+		}
+		c.gen++
+		heap.Push(&events, event{at: min64(now+ceilDiv(c.running.remaining, c.speed), c.sliceEnd), cpu: c, gen: c.gen})
+	}
+
+	// dispatch gives an idle CPU its next task, from its own queue or, if
+	// that is empty, from the busiest CPU with work waiting.
+	dispatch := func(c *cpu) {
+		t, slice := take(c, c.queue)
+		if t == nil && m.perCore {
+			busiest := make([]*cpu, 0, len(cpus))
+			for i := range cpus {
+				if cpus[i].running != nil && cpus[i].queue.queued > 0 {
+					busiest = append(busiest, &cpus[i])
+				}
+			}
+			sort.SliceStable(busiest, func(i, j int) bool { return busiest[i].queue.queued > busiest[j].queue.queued })
+			for _, from := range busiest {
+				if t, slice = take(c, from.queue); t != nil {
+					break
+				}
+			}
+		}
+		if t == nil {
+			return
+		}
+		if t.cpu >= 0 && t.cpu != c.id {
+			migr++
+		}
+		t.cpu = c.id
+		c.running, c.slice = t, slice
+		if c.last != nil && t != c.last {
+			switches++
+		}
+		if m.switchCost > 0 && c.last != nil && t != c.last {
+			switchTime += m.switchCost
+			gantt = append(gantt, TimeSlice{PID: t.ProcessID, Job: t.Job, CPU: c.id, Start: now, Stop: now + m.switchCost, Switch: true})
+			c.switching, c.switchEnd = true, now+m.switchCost
+			c.gen++
+			heap.Push(&events, event{at: c.switchEnd, cpu: c, gen: c.gen})
+		} else {
+			run(c)
+		}
+		c.last = t
+	}
+
+	// stop takes c's task off it, recording its slice, and returns the task.
+	stop := func(c *cpu) *task {
+		t := c.running
+		if now > c.runStart && !c.switching {
+			gantt = append(gantt, TimeSlice{PID: t.ProcessID, Job: t.Job, CPU: c.id, Start: c.runStart, Stop: now})
+		}
+		c.running, c.switching = nil, false
+		c.gen++
+		return t
+	}
+
+	for done < len(tasks) {
+		arrive()
+		// Arrivals only interrupt the run if a CPU could take them or they
+		// may preempt.
+		interrupt := false
+		for _, c := range order {
+			if c.running == nil {
+				dispatch(c)
+			}
+			interrupt = interrupt || c.running == nil
+		}
+
+		// Run until the next event.
+		var (
+			until  = int64(math.MaxInt64)
+			fireAt = int64(-1)
+			busy   = false
+		)
+		for i := range cpus {
+			if cpus[i].running != nil {
+				busy = true
+				interrupt = interrupt || cpus[i].queue.pre != nil
+			}
+		}
+		for len(events) > 0 && events[0].lapsed() {
+			heap.Pop(&events)
+		}
+		if len(events) > 0 {
+			until = events[0].at
+		}
+		if next < len(arrival) && interrupt {
+			until = min64(until, arrival[next].ArrivalTime)
+		}
+		if busy {
+			for _, q := range queues {
+				if q.tm == nil {
+					continue
+				}
+				if t, ok := q.tm.nextTimer(now); ok && t <= until {
+					until, fireAt = t, t
+				}
+			}
+		}
+
+		d := until - now
+		now = until
+		for len(events) > 0 && events[0].at <= now {
+			heap.Pop(&events)
+		}
+		for i := range cpus {
+			c := &cpus[i]
+			if c.running == nil || c.switching {
+				continue
+			}
+			c.running.remaining -= d * c.speed
+			if c.running.remaining < 0 {
+				// The burst ran out part way through the last tick.
+				c.running.remaining = 0
+			}
+			c.running.cpuTime += d
+			c.busy += d
+			c.expired = c.running.remaining > 0 && now == c.sliceEnd
+			c.queue.ran(c.running, d, c.expired)
+		}
+
+		// Arrivals and the end of I/O at this instant queue ahead of a task
+		// whose slice expired.
+		if doing != nil && now == ioEnd {
+			t := doing
+			doing = nil
+			t.blocked += now - t.blockedAt
+			advance(t)
+			startIO()
+		}
+		arrive()
+		if now == fireAt {
+			for i := range cpus {
+				if tm := cpus[i].queue.tm; tm != nil {
+					tm.fire(cpus[i].running, now)
+				}
+			}
+		}
+
+		for i := range cpus {
+			c := &cpus[i]
+			switch {
+			case c.running == nil:
+			case c.switching:
+				if now < c.switchEnd {
+					continue
+				}
+				if c.queue.pre != nil && c.queue.pre.preempts(c.running, now) {
+					c.queue.add(stop(c), now)
+					dispatch(c)
+				} else {
+					run(c)
+				}
+			case c.running.remaining == 0:
+				t := stop(c)
+				if t.phase+1 >= len(t.Bursts) {
+					c.completed++
+				}
+				advance(t)
+				dispatch(c)
+			case c.expired, c.queue.pre != nil && c.queue.pre.preempts(c.running, now):
+				c.queue.add(stop(c), now)
+				dispatch(c)
+			}
+		}
+	}
+
+	sort.SliceStable(gantt, func(i, j int) bool {
+		if gantt[i].Start != gantt[j].Start {
+			return gantt[i].Start < gantt[j].Start
+		}
+		return gantt[i].CPU < gantt[j].CPU
+	})
+	res := simResult(processes, tasks, gantt)
+	res.Stats.ContextSwitches = switches
+	if first, last := span(res.Processes); last > first {
+		var busy int64
+		for _, c := range cpus {
+			busy += c.busy
+		}
+		capacity := int64(len(cpus)) * (last - first)
+		res.Stats.Utilisation = float64(busy) / float64(capacity)
+		res.Stats.Idle = capacity - busy - switchTime
+	}
+	if len(io) > 0 {
+		res.IO = io
+	}
+	if len(cpus) > 1 {
+		res.CPUs, res.Migrations = len(cpus), migr
+	}
+	if len(cpus) > 1 || m.little > 0 {
+		res.Cores = make([]CoreStats, len(cpus))
+		first, last := span(res.Processes)
+		for i, c := range cpus {
+			res.Cores[i] = CoreStats{
+				CPU:       i,
+				Speed:     float64(c.speed) / float64(m.scale()),
+				Busy:      c.busy,
+				Energy:    float64(c.busy) * float64(m.power(i)),
+				Completed: c.completed,
+			}
+			if last > first {
+				res.Cores[i].Utilisation = float64(c.busy) / float64(last-first)
+			}
+		}
+	}
+	return res
+}
+
+type (
+	// cpu is a processor in a simulation.
+	cpu struct {
+		id    int
+		speed int64
+		queue *runQueue
+		// running is the task on the CPU, which is switching to it until
+		// switchEnd if switching is set, and otherwise has run since
+		// runStart. Its slice ends at sliceEnd, and expired is set when it
+		// has.
+		running            *task
+		slice              int64
+		runStart, sliceEnd int64
+		switching          bool
+		switchEnd          int64
+		expired            bool
+		// last is the task whose context the CPU holds.
+		last *task
+		// gen counts the changes of what the CPU is doing.
+		gen int
+		// busy is the ticks the CPU has spent running tasks, and completed
+		// the number of tasks that completed on it.
+		busy      int64
+		completed int
+	}
+
+	// event is a time the simulation must stop at: the end of a CPU's
+	// switch, slice or burst, or of the I/O device's work if cpu is nil.
+	event struct {
+		at  int64
+		cpu *cpu
+		// gen is the generation of the CPU the event is for; the event has
+		// lapsed if the CPU has since stopped or started something else.
+		gen int
+	}
+
+	// eventQueue is a min-heap of events by time.
+	eventQueue []event
+
+	// runQueue is a policy with a count of the tasks queued in it and their
+	// remaining burst.
+	runQueue struct {
+		policy
+		pre    preempter
+		tm     timer
+		queued int
+		work   int64
+	}
+)
+
+func (q *runQueue) add(t *task, now int64) {
+	q.push(t, now)
+	q.queued++
+	q.work += t.remaining
+}
+
+// work is the burst left of the tasks queued for or running on c.
+func (c *cpu) work() int64 {
+	if c.running != nil {
+		return c.queue.work + c.running.remaining
+	}
+	return c.queue.work
+}
+
+func (e event) lapsed() bool { return e.cpu != nil && e.gen != e.cpu.gen }
+
+func (q eventQueue) Len() int            { return len(q) }
+func (q eventQueue) Less(i, j int) bool  { return q[i].at < q[j].at }
+func (q eventQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *eventQueue) Push(x interface{}) { *q = append(*q, x.(event)) }
+
+func (q *eventQueue) Pop() interface{} {
+	old := *q
+	e := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return e
+}
+
+// load is the number of tasks queued for or running on c.
+func (c *cpu) load() int {
+	if c.running != nil {
+		return c.queue.queued + 1
+	}
+	return c.queue.queued
+}
+
+// ceilDiv returns a / b rounded up, for positive b.
+func ceilDiv(a, b int64) int64 { return (a + b - 1) / b }
+
+func min64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// simResult builds the Result of a finished simulation.
+func simResult(processes []Process, tasks []task, gantt []TimeSlice) Result {
+	var (
+		wait       = make([]int64, len(tasks))
+		completion = make([]int64, len(tasks))
+	)
+	for i := range tasks {
+		completion[i] = tasks[i].completion
+		wait[i] = completion[i] - tasks[i].ArrivalTime - tasks[i].cpuTime - tasks[i].blocked
+	}
+	res := newResult(processes, gantt, wait, completion)
+	for i := range tasks {
+		res.Processes[i].Blocked = tasks[i].blocked
+	}
+	return res
+}
+
+// fifo is a first-in, first-out queue of tasks.
+type fifo []*task
+
+func (q *fifo) push(t *task) { *q = append(*q, t) }
+
+func (q *fifo) pop() *task {
+	t := (*q)[0]
+	(*q)[0] = nil
+	*q = (*q)[1:]
+	return t
+}
+
+// pushFront puts t at the head of the queue, ahead of everything waiting.
+func (q *fifo) pushFront(t *task) { *q = append(fifo{t}, *q...) }
+
+// taskHeap is a priority queue of tasks, the least by less first.
+type taskHeap struct {
+	tasks []*task
+	less  func(a, b *task) bool
+}
+
+func (h *taskHeap) push(t *task) { heap.Push(h, t) }
+func (h *taskHeap) pop() *task   { return heap.Pop(h).(*task) }
+func (h *taskHeap) peek() *task  { return h.tasks[0] }
+
+func (h taskHeap) Len() int            { return len(h.tasks) }
+func (h taskHeap) Less(i, j int) bool  { return h.less(h.tasks[i], h.tasks[j]) }
+func (h taskHeap) Swap(i, j int)       { h.tasks[i], h.tasks[j] = h.tasks[j], h.tasks[i] }
+func (h *taskHeap) Push(x interface{}) { h.tasks = append(h.tasks, x.(*task)) }
+
+func (h *taskHeap) Pop() interface{} {
+	t := h.tasks[len(h.tasks)-1]
+	h.tasks[len(h.tasks)-1] = nil
+	h.tasks = h.tasks[:len(h.tasks)-1]
+	return t
+}
+
+// orderPolicy runs the ready task that comes first by less, and if
+// preemptive takes the CPU for any task that arrives ahead of the running one.
+type orderPolicy struct {
+	ready      taskHeap
+	preemptive bool
+}
+
+// orderBy returns a constructor of orderPolicy run queues.
+func orderBy(less func(a, b *task) bool, preemptive bool) func() policy {
+	return func() policy {
+		return &orderPolicy{ready: taskHeap{less: less}, preemptive: preemptive}
+	}
+}
+
+func (p *orderPolicy) push(t *task, _ int64)    { p.ready.push(t) }
+func (p *orderPolicy) pop(int64) (*task, int64) { return p.ready.pop(), 0 }
+func (p *orderPolicy) empty() bool              { return p.ready.Len() == 0 }
+func (p *orderPolicy) ran(*task, int64, bool)   {}
+
+func (p *orderPolicy) preempts(running *task, _ int64) bool {
+	return p.preemptive && p.ready.Len() > 0 && p.ready.less(p.ready.peek(), running)
+}
+
+// byArrival breaks ties between tasks by arrival, then workload order.
+func byArrival(a, b *task) bool {
+	if a.ArrivalTime != b.ArrivalTime {
+		return a.ArrivalTime < b.ArrivalTime
+	}
+	return a.index < b.index
+}
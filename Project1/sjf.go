@@ -0,0 +1,62 @@
+package main
+
+import "io"
+
+func init() {
+	Register("sjf", func(params Parameters) (Scheduler, error) {
+		m, err := newMachine(params)
+		return sjf{m}, err
+	})
+	Register("sjf-np", func(params Parameters) (Scheduler, error) {
+		m, err := newMachine(params)
+		return sjfNP{m}, err
+	})
+}
+
+// sjf always runs the arrived process with the least remaining time,
+// preempting the running process when a shorter one arrives.
+type sjf struct {
+	machine
+}
+
+func (sjf) Name() string             { return "sjf" }
+func (sjf) Title() string            { return "Shortest-job-first (preemptive)" }
+func (s sjf) Parameters() Parameters { return s.parameters(Parameters{}) }
+
+func (s sjf) Schedule(processes []Process) Result {
+	return s.simulate(processes, orderBy(shorterRemaining, true))
+}
+
+// Implement SJF (preemptive) and report average turnaround time, average waiting time, and average throughput.
+func SJFSchedule(w io.Writer, title string, processes []Process) {
+	outputResult(w, title, sjf{}.Schedule(processes))
+}
+
+// sjfNP runs the arrived process with the shortest burst to completion.
+type sjfNP struct {
+	machine
+}
+
+func (sjfNP) Name() string             { return "sjf-np" }
+func (sjfNP) Title() string            { return "Shortest-job-first (non-preemptive)" }
+func (s sjfNP) Parameters() Parameters { return s.parameters(Parameters{}) }
+
+func (s sjfNP) Schedule(processes []Process) Result {
+	return s.simulate(processes, orderBy(shorterJob, false))
+}
+
+// shorterJob orders tasks by burst, then arrival.
+func shorterJob(a, b *task) bool {
+	if a.BurstDuration != b.BurstDuration {
+		return a.BurstDuration < b.BurstDuration
+	}
+	return byArrival(a, b)
+}
+
+// shorterRemaining orders tasks by remaining time, then arrival.
+func shorterRemaining(a, b *task) bool {
+	if a.remaining != b.remaining {
+		return a.remaining < b.remaining
+	}
+	return byArrival(a, b)
+}
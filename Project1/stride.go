@@ -0,0 +1,112 @@
+package main
+
+import "container/heap"
+
+func init() {
+	Register("stride", newStride)
+}
+
+// strideOne is divided by a process's tickets to give its stride.
+const strideOne = 1 << 20
+
+// stride is deterministic proportional-share scheduling: every quantum the
+// ready process with the lowest pass runs, and its pass then advances by its
+// stride, which is inversely proportional to its tickets.
+type stride struct {
+	machine
+	quantum int64
+}
+
+// newStride builds a stride scheduler from the parameters:
+// • quantum: ticks between decisions (default 1)
+func newStride(params Parameters) (Scheduler, error) {
+	quantum, err := params.positive("quantum", 1)
+	if err != nil {
+		return nil, err
+	}
+	m, err := newMachine(params)
+	return stride{machine: m, quantum: quantum}, err
+}
+
+func (stride) Name() string             { return "stride" }
+func (stride) Title() string            { return "Stride" }
+func (s stride) Parameters() Parameters { return s.parameters(Parameters{"quantum": s.quantum}) }
+
+func (s stride) Schedule(processes []Process) Result {
+	// Processes keep their pass when they move between CPUs.
+	pass := make(map[*task]int64, len(processes))
+	res := s.simulate(processes, func() policy {
+		return &stridePolicy{stride: s, pass: pass}
+	})
+	res.Shares = shares(res.Processes)
+	return res
+}
+
+// stridePolicy is the simulation state of a stride scheduler.
+type stridePolicy struct {
+	stride
+	ready passQueue
+	pass  map[*task]int64
+	// global is the pass of the last process to run, given to arrivals so
+	// they neither starve the others nor are starved by them.
+	global int64
+	seq    int64
+}
+
+func (p *stridePolicy) push(t *task, _ int64) {
+	if _, ok := p.pass[t]; !ok {
+		p.pass[t] = p.global
+	}
+	p.seq++
+	heap.Push(&p.ready, passEntry{t: t, pass: p.pass[t], seq: p.seq})
+}
+
+// pop takes the lowest pass, the longest waiting on ties.
+func (p *stridePolicy) pop(int64) (*task, int64) {
+	t := heap.Pop(&p.ready).(passEntry).t
+	p.global = p.pass[t]
+	return t, p.quantum
+}
+
+func (p *stridePolicy) empty() bool { return len(p.ready) == 0 }
+
+func (p *stridePolicy) ran(t *task, d int64, _ bool) {
+	p.pass[t] += d * passStride(t.Process)
+}
+
+// passStride is how far a process's pass advances for each tick it runs. It
+// is at least 1, or a process with more than strideOne tickets would never
+// advance and would starve the rest.
+func passStride(p Process) int64 {
+	return max64(strideOne/tickets(p), 1)
+}
+
+// passEntry is a ready process ordered by pass, then by how long it has been
+// waiting. Only the running process's pass changes.
+type passEntry struct {
+	t    *task
+	pass int64
+	seq  int64
+}
+
+// passQueue is a min-heap of ready processes.
+type passQueue []passEntry
+
+func (q passQueue) Len() int { return len(q) }
+
+func (q passQueue) Less(i, j int) bool {
+	if q[i].pass != q[j].pass {
+		return q[i].pass < q[j].pass
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q passQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *passQueue) Push(x interface{}) { *q = append(*q, x.(passEntry)) }
+
+func (q *passQueue) Pop() interface{} {
+	old := *q
+	e := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return e
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStride_Schedule(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 4, Tickets: 3},
+		{ProcessID: 2, BurstDuration: 4, Tickets: 1},
+	}
+	got := stride{quantum: 1}.Schedule(processes)
+	wantGantt := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 1},
+		{PID: 2, Start: 1, Stop: 2},
+		{PID: 1, Start: 2, Stop: 3},
+		{PID: 1, Start: 3, Stop: 4},
+		{PID: 1, Start: 4, Stop: 5},
+		{PID: 2, Start: 5, Stop: 6},
+		{PID: 2, Start: 6, Stop: 7},
+		{PID: 2, Start: 7, Stop: 8},
+	}
+	if !reflect.DeepEqual(got.Gantt, wantGantt) {
+		t.Errorf("Gantt = %v, want %v", got.Gantt, wantGantt)
+	}
+	if len(got.Shares) != len(processes) {
+		t.Errorf("Shares = %v, want one per process", got.Shares)
+	}
+}
+
+func TestStride_Schedule_moreTicketsThanStrideOne(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 3, Tickets: 4 * strideOne},
+		{ProcessID: 2, BurstDuration: 3, Tickets: strideOne},
+	}
+	got := stride{quantum: 1}.Schedule(processes)
+	wantGantt := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 1},
+		{PID: 2, Start: 1, Stop: 2},
+		{PID: 1, Start: 2, Stop: 3},
+		{PID: 2, Start: 3, Stop: 4},
+		{PID: 1, Start: 4, Stop: 5},
+		{PID: 2, Start: 5, Stop: 6},
+	}
+	if !reflect.DeepEqual(got.Gantt, wantGantt) {
+		t.Errorf("Gantt = %v, want %v", got.Gantt, wantGantt)
+	}
+}
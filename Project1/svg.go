@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"math"
+)
+
+// Layout of an SVG Gantt chart, in pixels.
+const (
+	svgWidth       = 800
+	svgMargin      = 20
+	svgTitleHeight = 24
+	svgBarHeight   = 32
+	svgAxisHeight  = 28
+)
+
+// svgPalette colours slices by PID, so a process keeps its colour across the
+// charts of every algorithm.
+var svgPalette = []string{
+	"#4e79a7", "#f28e2b", "#e15759", "#76b7b2", "#59a14f", "#edc948",
+	"#b07aa1", "#ff9da7", "#9c755f", "#bab0ac", "#86bcb6", "#d37295",
+}
+
+func init() {
+	formats["svg"] = writeSVG
+}
+
+// pidColour returns the fill colour of a process.
+func pidColour(pid int64) string {
+	i := pid % int64(len(svgPalette))
+	if i < 0 {
+		i += int64(len(svgPalette))
+	}
+	return svgPalette[i]
+}
+
+// svgLanes returns the number of bars in the Gantt chart of run: one per CPU,
+// and one for the I/O device when processes do I/O.
+func svgLanes(run Run) int {
+	if len(run.IO) > 0 {
+		return ganttLanes(run.Gantt, run.CPUs) + 1
+	}
+	return ganttLanes(run.Gantt, run.CPUs)
+}
+
+// svgChartHeight returns the height of the Gantt chart of run.
+func svgChartHeight(run Run) int {
+	return svgTitleHeight + svgLanes(run)*svgBarHeight + svgAxisHeight + svgMargin
+}
+
+// writeSVG writes a single SVG document holding one Gantt chart per run,
+// stacked vertically.
+func writeSVG(out Output, runs []Run) error {
+	height := 0
+	for _, run := range runs {
+		height += svgChartHeight(run)
+	}
+	bw := bufio.NewWriter(out.W)
+	_, _ = fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		svgWidth, height, svgWidth, height)
+	y := 0
+	for _, run := range runs {
+		writeGanttSVG(bw, run, y)
+		y += svgChartHeight(run)
+	}
+	_, _ = fmt.Fprintln(bw, "</svg>")
+	return bw.Flush()
+}
+
+// writeGanttSVG writes the Gantt chart of run as a nested <svg> placed y
+// pixels from the top. Slice widths are proportional to their duration and
+// gaps where no process runs are drawn as idle time, and context switches in
+// grey. A multiprocessor schedule has a bar per CPU, and I/O is drawn faded on
+// a bar of its own. Deadlines are marked under the bars.
+func writeGanttSVG(w io.Writer, run Run, y int) {
+	end := ganttEnd(run.Gantt)
+	if e := ganttEnd(run.IO); e > end {
+		end = e
+	}
+	for _, row := range run.Processes {
+		if row.Deadline > end {
+			end = row.Deadline
+		}
+	}
+	var (
+		scale = float64(svgWidth-2*svgMargin) / math.Max(float64(end), 1)
+		lanes = svgLanes(run)
+		top   = func(cpu int) int { return svgTitleHeight + cpu*svgBarHeight }
+		x     = func(t int64) float64 { return svgMargin + float64(t)*scale }
+	)
+	_, _ = fmt.Fprintf(w, `<svg y="%d" width="%d" height="%d" font-family="sans-serif" font-size="12">`+"\n",
+		y, svgWidth, svgChartHeight(run))
+	_, _ = fmt.Fprintf(w, `<text x="%d" y="%d" font-size="14" font-weight="bold">%s</text>`+"\n",
+		svgMargin, svgTitleHeight-8, html.EscapeString(run.Title))
+
+	// Idle gaps, then the slices themselves.
+	last := make([]int64, lanes)
+	for _, slice := range run.Gantt {
+		if slice.Start > last[slice.CPU] {
+			_, _ = fmt.Fprintf(w, `<rect x="%.2f" y="%d" width="%.2f" height="%d" fill="#eeeeee" stroke="#999999" stroke-dasharray="3,2"><title>idle %d-%d</title></rect>`+"\n",
+				x(last[slice.CPU]), top(slice.CPU), x(slice.Start)-x(last[slice.CPU]), svgBarHeight, last[slice.CPU], slice.Start)
+		}
+		if slice.Stop > last[slice.CPU] {
+			last[slice.CPU] = slice.Stop
+		}
+	}
+	for _, slice := range run.Gantt {
+		width := x(slice.Stop) - x(slice.Start)
+		if slice.Switch {
+			_, _ = fmt.Fprintf(w, `<rect x="%.2f" y="%d" width="%.2f" height="%d" fill="#555555" stroke="#ffffff"><title>switch to PID %s: %d-%d</title></rect>`+"\n",
+				x(slice.Start), top(slice.CPU), width, svgBarHeight, jobLabel(slice.PID, slice.Job), slice.Start, slice.Stop)
+			continue
+		}
+		_, _ = fmt.Fprintf(w, `<rect x="%.2f" y="%d" width="%.2f" height="%d" fill="%s" stroke="#ffffff"><title>PID %s: %d-%d</title></rect>`+"\n",
+			x(slice.Start), top(slice.CPU), width, svgBarHeight, pidColour(slice.PID), slice.label(), slice.Start, slice.Stop)
+		if label := slice.label(); width >= float64(8*len(label)) {
+			_, _ = fmt.Fprintf(w, `<text x="%.2f" y="%d" text-anchor="middle" fill="#ffffff">%s</text>`+"\n",
+				x(slice.Start)+width/2, top(slice.CPU)+svgBarHeight/2+4, label)
+		}
+	}
+
+	for _, slice := range run.IO {
+		_, _ = fmt.Fprintf(w, `<rect x="%.2f" y="%d" width="%.2f" height="%d" fill="%s" fill-opacity="0.5" stroke="#ffffff"><title>I/O PID %s: %d-%d</title></rect>`+"\n",
+			x(slice.Start), top(lanes-1), x(slice.Stop)-x(slice.Start), svgBarHeight, pidColour(slice.PID), slice.label(), slice.Start, slice.Stop)
+	}
+
+	// Deadlines, as ticks below the bars in the colour of their process.
+	for _, row := range run.Processes {
+		if row.Deadline <= 0 {
+			continue
+		}
+		_, _ = fmt.Fprintf(w, `<line x1="%.2f" y1="%d" x2="%.2f" y2="%d" stroke="%s" stroke-width="2"><title>deadline %s: %d</title></line>`+"\n",
+			x(row.Deadline), top(0), x(row.Deadline), top(lanes)+4, pidColour(row.ProcessID), row.label(), row.Deadline)
+	}
+
+	// Time axis.
+	axis := top(lanes) + 4
+	_, _ = fmt.Fprintf(w, `<line x1="%.2f" y1="%d" x2="%.2f" y2="%d" stroke="#333333"/>`+"\n",
+		x(0), axis, x(end), axis)
+	step := tickStep(end)
+	for t := int64(0); t <= end; t += step {
+		_, _ = fmt.Fprintf(w, `<line x1="%.2f" y1="%d" x2="%.2f" y2="%d" stroke="#333333"/>`+"\n",
+			x(t), axis, x(t), axis+4)
+		_, _ = fmt.Fprintf(w, `<text x="%.2f" y="%d" text-anchor="middle">%d</text>`+"\n",
+			x(t), axis+16, t)
+	}
+	_, _ = fmt.Fprintln(w, "</svg>")
+}
+
+// ganttLanes returns the number of CPUs a Gantt chart needs a lane for: cpus,
+// or more if gantt has slices on higher CPUs.
+func ganttLanes(gantt []TimeSlice, cpus int) int {
+	lanes := cpus
+	if lanes < 1 {
+		lanes = 1
+	}
+	for _, slice := range gantt {
+		if slice.CPU >= lanes {
+			lanes = slice.CPU + 1
+		}
+	}
+	return lanes
+}
+
+// ganttEnd returns the time the last slice of gantt stops.
+func ganttEnd(gantt []TimeSlice) (end int64) {
+	for _, slice := range gantt {
+		if slice.Stop > end {
+			end = slice.Stop
+		}
+	}
+	return end
+}
+
+// tickStep returns a 1, 2 or 5 times power of ten step that puts about ten
+// ticks on an axis running to end.
+func tickStep(end int64) int64 {
+	step := int64(1)
+	for {
+		for _, m := range []int64{1, 2, 5} {
+			if end/(step*m) <= 10 {
+				return step * m
+			}
+		}
+		step *= 10
+	}
+}
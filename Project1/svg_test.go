@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+func Test_tickStep(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		end  int64
+		want int64
+	}{
+		{end: 0, want: 1},
+		{end: 7, want: 1},
+		{end: 20, want: 2},
+		{end: 45, want: 5},
+		{end: 100, want: 10},
+		{end: 1500, want: 200},
+	}
+	for _, tt := range tests {
+		if got := tickStep(tt.end); got != tt.want {
+			t.Errorf("tickStep(%d) = %d, want %d", tt.end, got, tt.want)
+		}
+	}
+}
+
+func Test_pidColour(t *testing.T) {
+	t.Parallel()
+	if pidColour(1) == pidColour(2) {
+		t.Error("pidColour() gave neighbouring PIDs the same colour")
+	}
+	if got, want := pidColour(-1), pidColour(int64(len(svgPalette)-1)); got != want {
+		t.Errorf("pidColour(-1) = %v, want %v", got, want)
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	t.Parallel()
+	runs := []Run{
+		{
+			Title: "Gaps & <idle>",
+			Result: Result{Gantt: []TimeSlice{
+				{PID: 1, Start: 2, Stop: 4},
+				{PID: 2, Start: 4, Stop: 5},
+				{PID: 1, Start: 8, Stop: 10},
+			}},
+		},
+		newRun(fcfs{}, exampleProcesses),
+		{
+			Title: "Two CPUs",
+			Result: Result{CPUs: 2, Gantt: []TimeSlice{
+				{PID: 3, Start: 0, Stop: 5},
+				{PID: 4, Start: 1, Stop: 5, CPU: 1},
+			}},
+		},
+		{
+			Title: "I/O",
+			Result: Result{
+				Gantt: []TimeSlice{{PID: 5, Start: 0, Stop: 2}},
+				IO:    []TimeSlice{{PID: 5, Start: 2, Stop: 4}},
+			},
+		},
+	}
+	var w bytes.Buffer
+	if err := writeRuns("svg", Output{W: &w}, runs); err != nil {
+		t.Fatalf("writeRuns() unexpected error: %v", err)
+	}
+
+	// The document must be well formed XML.
+	dec := xml.NewDecoder(bytes.NewReader(w.Bytes()))
+	charts := 0
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("invalid SVG: %v", err)
+		}
+		if el, ok := tok.(xml.StartElement); ok && el.Name.Local == "svg" {
+			charts++
+		}
+	}
+	if charts != len(runs)+1 {
+		t.Errorf("got %d <svg> elements, want %d", charts, len(runs)+1)
+	}
+
+	got := w.String()
+	for _, want := range []string{
+		"Gaps &amp; &lt;idle&gt;",
+		"<title>idle 0-2</title>",
+		"<title>idle 5-8</title>",
+		// 760px across 10 ticks: a 2 tick slice is twice as wide as a 1 tick one.
+		`width="152.00" height="32" fill="` + pidColour(1),
+		`width="76.00" height="32" fill="` + pidColour(2),
+		// Each CPU has its own bar, and the chart grows to fit them.
+		`<rect x="172.00" y="56" width="608.00" height="32" fill="` + pidColour(4),
+		"<title>idle 0-1</title>",
+		`height="480"`,
+		"<title>I/O PID 5: 2-4</title>",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("writeSVG() missing %q", want)
+		}
+	}
+}